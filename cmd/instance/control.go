@@ -0,0 +1,379 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"light-launcher/internal/config"
+	"light-launcher/internal/executor"
+	"light-launcher/internal/executor/builder"
+	"light-launcher/internal/session"
+	"light-launcher/internal/system"
+	"light-launcher/internal/types"
+)
+
+const (
+	// usageSampleInterval is how often monitorUsage samples the session.
+	usageSampleInterval = 2 * time.Second
+	// usageHistorySize keeps five minutes of samples.
+	usageHistorySize = 150
+)
+
+// sessionController answers control socket requests for the running game.
+type sessionController struct {
+	mutex       sync.Mutex
+	record      types.RunningSession
+	process     *os.Process
+	running     bool
+	logTerminal *exec.Cmd
+
+	stopMutex     sync.Mutex
+	stopOptions   executor.StopOptions
+	stopResult    *types.StopResult
+	stopRequested atomic.Bool
+
+	lastSampleValues system.ProcessSample
+	lastSample       time.Time
+	lastSource       string
+	usageHistory     []types.SessionUsage
+	scopeCgroup      string
+}
+
+func newSessionController(options types.LaunchOptions, logPath string, process *os.Process) *sessionController {
+	name := options.Name
+	if name == "" {
+		name = filepath.Base(options.GamePath)
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+
+	// Only umu-run and Proton run the game with the selected Proton build.
+	var protonPath, protonVersion string
+	backend := builder.GetBackend(options)
+	if options.ProtonPath != "" && (backend.Name() == builder.BackendUmu || backend.Name() == builder.BackendProton) {
+		protonPath = config.ExpandPath(options.ProtonPath)
+		protonVersion = filepath.Base(protonPath)
+	}
+
+	stopOptions := executor.NewStopOptions(options, time.Duration(config.LoadAppSettings().StopTimeoutSeconds)*time.Second)
+	stopOptions.ProtonPath = protonPath
+	stopOptions.Wineserver = backend.Wineserver(options)
+	// Empty for native games, which have no Wine processes to reach with
+	// wineserver -k.
+	stopOptions.WinePrefix = builder.WinePrefix(options)
+
+	pidStartTime, _ := system.GetProcessStartTime(os.Getpid())
+	gamePidStartTime, _ := system.GetProcessStartTime(process.Pid)
+	return &sessionController{
+		record: types.RunningSession{
+			Pid:              os.Getpid(),
+			PidStartTime:     pidStartTime,
+			GamePid:          process.Pid,
+			GamePidStartTime: gamePidStartTime,
+			GameID:           options.ID,
+			GamePath:         filepath.Clean(options.GamePath),
+			GameName:         name,
+			ProtonPath:       protonPath,
+			ProtonVersion:    protonVersion,
+			PrefixPath:       config.ExpandPath(options.PrefixPath),
+			WinePrefix:       stopOptions.WinePrefix,
+			Wineserver:       stopOptions.Wineserver,
+			Wrappers:         builder.WrapperChain(options),
+			LogPath:          logPath,
+			StartedAt:        time.Now().Unix(),
+		},
+		process:     process,
+		running:     true,
+		stopOptions: stopOptions,
+	}
+}
+
+// register publishes the session in the runtime registry.
+func (controller *sessionController) register() {
+	if err := session.Register(controller.Status()); err != nil {
+		log.Printf("Failed to register session: %v\n", err)
+	}
+}
+
+func (controller *sessionController) unregister() {
+	session.Unregister(controller.record.Pid)
+}
+
+func (controller *sessionController) Status() types.RunningSession {
+	controller.mutex.Lock()
+	defer controller.mutex.Unlock()
+
+	controller.findScope()
+	return controller.status()
+}
+
+// status returns the registry record with the current state. Callers hold
+// the mutex.
+func (controller *sessionController) status() types.RunningSession {
+	status := controller.record
+	status.Running = controller.running
+	status.ShowingLogs = controller.logTerminal != nil
+	return status
+}
+
+// Stop runs the escalating shutdown sequence. Only one runs at a time; a
+// second request waits for the first and reports the same outcome once the
+// game is stopped, or retries the sequence when the first one failed.
+func (controller *sessionController) Stop() (types.StopResult, error) {
+	controller.stopRequested.Store(true)
+	controller.stopMutex.Lock()
+	defer controller.stopMutex.Unlock()
+
+	if controller.stopResult != nil {
+		return *controller.stopResult, nil
+	}
+
+	result := executor.StopProcessGroup(controller.process, controller.stopOptions)
+	log.Printf("Stop sequence finished: stage=%q stopped=%v wineserver=%v\n", result.Stage, result.Stopped, result.WineserverKill)
+	if !result.Stopped {
+		// Leave the result uncached so a later request runs the sequence again.
+		return result, errors.New(result.Error)
+	}
+	controller.stopResult = &result
+	return result, nil
+}
+
+// waitForStop blocks until a running stop sequence, including its wineserver
+// cleanup, has finished.
+func (controller *sessionController) waitForStop() {
+	controller.stopMutex.Lock()
+	controller.stopMutex.Unlock()
+}
+
+// wasStopped reports whether the game exited because it was asked to stop,
+// so the signal it died from is not a crash.
+func (controller *sessionController) wasStopped() bool {
+	return controller.stopRequested.Load()
+}
+
+func (controller *sessionController) Kill() error {
+	log.Println("Force kill requested over control socket")
+	controller.stopRequested.Store(true)
+	return syscall.Kill(-controller.process.Pid, syscall.SIGKILL)
+}
+
+// ToggleLogs opens the log terminal, or closes it when it is already open.
+func (controller *sessionController) ToggleLogs() (bool, error) {
+	controller.mutex.Lock()
+	defer controller.mutex.Unlock()
+
+	if controller.logTerminal != nil {
+		_ = controller.logTerminal.Process.Kill()
+		controller.logTerminal = nil
+		return false, nil
+	}
+
+	terminal := startLogTerminal(controller.record.LogPath, controller.process.Pid)
+	if terminal == nil {
+		return false, fmt.Errorf("no terminal emulator found")
+	}
+	controller.watchLogTerminal(terminal)
+	return true, nil
+}
+
+// watchLogTerminal forgets the terminal once the user closes it. The caller
+// must hold the mutex.
+func (controller *sessionController) watchLogTerminal(terminal *exec.Cmd) {
+	controller.logTerminal = terminal
+	go func() {
+		_ = terminal.Wait()
+		controller.mutex.Lock()
+		if controller.logTerminal == terminal {
+			controller.logTerminal = nil
+		}
+		controller.mutex.Unlock()
+	}()
+}
+
+func (controller *sessionController) LogTail(lines int) ([]string, error) {
+	data, err := os.ReadFile(controller.record.LogPath)
+	if err != nil {
+		return nil, err
+	}
+
+	logLines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(logLines) > lines {
+		logLines = logLines[len(logLines)-lines:]
+	}
+	return logLines, nil
+}
+
+// Usage returns the latest sample taken by monitorUsage, or takes one when
+// monitoring has not produced any yet.
+func (controller *sessionController) Usage() (types.SessionUsage, error) {
+	controller.mutex.Lock()
+	defer controller.mutex.Unlock()
+
+	if len(controller.usageHistory) > 0 {
+		return controller.usageHistory[len(controller.usageHistory)-1], nil
+	}
+	return controller.sampleUsage(), nil
+}
+
+// Metrics returns the recent samples, oldest first, for graphs.
+func (controller *sessionController) Metrics() []types.SessionUsage {
+	controller.mutex.Lock()
+	defer controller.mutex.Unlock()
+
+	return append([]types.SessionUsage{}, controller.usageHistory...)
+}
+
+// monitorUsage samples the session every interval until the game exits and
+// passes each sample to onSample.
+func (controller *sessionController) monitorUsage(interval time.Duration, onSample func(types.SessionUsage)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		controller.mutex.Lock()
+		if !controller.running {
+			controller.mutex.Unlock()
+			return
+		}
+		usage := controller.sampleUsage()
+		controller.mutex.Unlock()
+		onSample(usage)
+	}
+}
+
+// sampleUsage reads the game's scope cgroup, or its process group when it has
+// no scope, and records the result in the history. Rates are measured since
+// the previous sample, so the first one averages over the whole session.
+// Callers hold the mutex.
+func (controller *sessionController) sampleUsage() types.SessionUsage {
+	controller.findScope()
+	sample := system.GetProcessGroupSample(controller.process.Pid)
+	source := "process-group"
+	if controller.scopeCgroup != "" {
+		if scopeSample, err := system.GetCgroupSample(controller.scopeCgroup); err == nil {
+			sample = scopeSample
+			source = "cgroup"
+		}
+	}
+	// Counters from different sources cannot be subtracted, so a switch,
+	// usually to the scope once it shows up, starts the deltas over.
+	if source != controller.lastSource {
+		controller.lastSampleValues = system.ProcessSample{}
+		controller.lastSample = time.Time{}
+		controller.lastSource = source
+	}
+	now := time.Now()
+	since := controller.lastSample
+	if since.IsZero() {
+		since = time.Unix(controller.record.StartedAt, 0)
+	}
+
+	usage := types.SessionUsage{
+		Timestamp:   now.Unix(),
+		Processes:   sample.Processes,
+		Threads:     sample.Threads,
+		MemoryBytes: sample.MemoryBytes,
+		VramBytes:   sample.VramBytes,
+		ReadBytes:   sample.ReadBytes,
+		WriteBytes:  sample.WriteBytes,
+		Source:      source,
+	}
+	if elapsed := now.Sub(since).Seconds(); elapsed > 0 {
+		if sample.CpuTicks >= controller.lastSampleValues.CpuTicks {
+			cpuSeconds := float64(sample.CpuTicks-controller.lastSampleValues.CpuTicks) / system.ClockTicksPerSecond
+			usage.CpuPercent = 100 * cpuSeconds / elapsed
+		}
+		if sample.ReadBytes >= controller.lastSampleValues.ReadBytes {
+			usage.ReadBytesPerSec = float64(sample.ReadBytes-controller.lastSampleValues.ReadBytes) / elapsed
+		}
+		if sample.WriteBytes >= controller.lastSampleValues.WriteBytes {
+			usage.WriteBytesPerSec = float64(sample.WriteBytes-controller.lastSampleValues.WriteBytes) / elapsed
+		}
+	}
+
+	controller.lastSampleValues = sample
+	controller.lastSample = now
+	controller.usageHistory = append(controller.usageHistory, usage)
+	if len(controller.usageHistory) > usageHistorySize {
+		controller.usageHistory = controller.usageHistory[len(controller.usageHistory)-usageHistorySize:]
+	}
+	return usage
+}
+
+// findScope looks up the systemd scope the game was moved into. systemd-run
+// moves itself before starting the game, so the scope only shows up in the
+// game's cgroup shortly after launch, and the record is saved again once it
+// does so registry readers see the scope too. Callers hold the mutex.
+func (controller *sessionController) findScope() {
+	if controller.scopeCgroup != "" || !controller.running {
+		return
+	}
+	cgroup, err := system.GetProcessCgroup(controller.process.Pid)
+	if err != nil {
+		return
+	}
+	unit := filepath.Base(cgroup)
+	if strings.HasPrefix(unit, builder.ScopeUnitPrefix) && strings.HasSuffix(unit, ".scope") {
+		controller.scopeCgroup = cgroup
+		controller.record.ScopeUnit = unit
+		if err := session.Register(controller.status()); err != nil {
+			log.Printf("Failed to register session: %v\n", err)
+		}
+	}
+}
+
+func (controller *sessionController) markExited() {
+	controller.mutex.Lock()
+	controller.running = false
+	controller.mutex.Unlock()
+}
+
+// recordPlaySession appends the finished session to the game's history.
+// Games launched without a name or ID have no config directory to write to.
+func (controller *sessionController) recordPlaySession(options types.LaunchOptions, exitCode int, crashed bool) {
+	if options.Name == "" && options.ID == "" {
+		return
+	}
+
+	endedAt := time.Now().Unix()
+	playSession := types.PlaySession{
+		StartedAt:       controller.record.StartedAt,
+		EndedAt:         endedAt,
+		DurationSeconds: endedAt - controller.record.StartedAt,
+		ExitCode:        exitCode,
+		Crashed:         crashed,
+		ProtonVersion:   controller.record.ProtonVersion,
+	}
+	if err := config.AppendPlaySession(options.Name, options.ID, playSession); err != nil {
+		log.Printf("Failed to record play session: %v\n", err)
+	}
+}
+
+// formatUsage renders a sample on one line for the tray tooltip.
+func formatUsage(usage types.SessionUsage) string {
+	line := fmt.Sprintf("CPU %.0f%% | RAM %s", usage.CpuPercent, formatBytes(float64(usage.MemoryBytes)))
+	if usage.VramBytes > 0 {
+		line += " | VRAM " + formatBytes(float64(usage.VramBytes))
+	}
+	return line + fmt.Sprintf(" | IO %s/s | %d threads", formatBytes(usage.ReadBytesPerSec+usage.WriteBytesPerSec), usage.Threads)
+}
+
+func formatBytes(bytes float64) string {
+	units := []string{"B", "KB", "MB", "GB", "TB"}
+	unit := 0
+	for bytes >= 1024 && unit < len(units)-1 {
+		bytes /= 1024
+		unit++
+	}
+	if unit == 0 {
+		return fmt.Sprintf("%.0f %s", bytes, units[unit])
+	}
+	return fmt.Sprintf("%.1f %s", bytes, units[unit])
+}
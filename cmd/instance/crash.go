@@ -0,0 +1,73 @@
+package main
+
+import (
+	"log"
+	"os"
+	"regexp"
+	"strings"
+
+	"light-launcher/internal/config"
+	"light-launcher/internal/crash"
+	"light-launcher/internal/executor"
+	"light-launcher/internal/types"
+)
+
+// diagnoseExit checks the session log for known failure signatures. Crash
+// reports are saved next to the game's config so the launcher can show the
+// last one.
+func diagnoseExit(opts types.LaunchOptions, logPath string, waitErr error) types.CrashReport {
+	if err := crash.LoadRules(config.GetCrashRulesPath()); err != nil {
+		log.Printf("Failed to load custom crash rules: %v\n", err)
+	}
+
+	var logLines []string
+	if data, err := os.ReadFile(logPath); err == nil {
+		logLines = gameOutputLines(strings.Split(string(data), "\n"))
+	}
+
+	report := crash.Analyze(executor.ExitCode(waitErr), executor.ExitSignal(waitErr), logLines)
+	report.GameID = opts.ID
+	report.GameName = opts.Name
+	report.LogPath = logPath
+
+	if !report.Crashed {
+		return report
+	}
+
+	log.Printf("!!! Crash detected: %s\n", report.Summary)
+	for _, finding := range report.Findings {
+		log.Printf("!!!   %s: %s\n", finding.Title, finding.Line)
+	}
+	if opts.Name != "" || opts.ID != "" {
+		if err := config.SaveCrashReport(opts.Name, opts.ID, report); err != nil {
+			log.Printf("Failed to save crash report: %v\n", err)
+		}
+	}
+	return report
+}
+
+// launcherLogLine matches the timestamp the log package puts in front of
+// LightLauncher's own lines, such as the command and [env] listing.
+var launcherLogLine = regexp.MustCompile(`^\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2} `)
+
+// gameOutputLines drops LightLauncher's own log lines, leaving what the game
+// and its wrappers wrote.
+func gameOutputLines(lines []string) []string {
+	output := lines[:0]
+	for _, line := range lines {
+		if !launcherLogLine.MatchString(line) {
+			output = append(output, line)
+		}
+	}
+	return output
+}
+
+func crashNotificationMessage(gameName string, report types.CrashReport) string {
+	message := gameName + ": " + report.Summary
+	for _, finding := range report.Findings {
+		if finding.Fatal || len(report.Findings) == 1 {
+			return message + "\n" + finding.Hint
+		}
+	}
+	return message
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"light-launcher/internal/executor/builder"
+	"light-launcher/internal/types"
+)
+
+// environmentFlag collects --env and --unset-env into one list so the
+// order given on the command line is kept.
+type environmentFlag struct {
+	variables *[]types.EnvironmentVariable
+	unset     bool
+}
+
+func (f environmentFlag) String() string {
+	if f.variables == nil {
+		return ""
+	}
+	var parts []string
+	for _, variable := range *f.variables {
+		if variable.Unset == f.unset {
+			parts = append(parts, variable.Key)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+func (f environmentFlag) Set(value string) error {
+	if f.unset {
+		key := strings.TrimSpace(value)
+		if key == "" || strings.Contains(key, "=") {
+			return fmt.Errorf("invalid variable name %q", value)
+		}
+		*f.variables = append(*f.variables, types.EnvironmentVariable{Key: key, Unset: true})
+		return nil
+	}
+
+	variable, ok := builder.ParseEnvironmentVariable(value)
+	if !ok {
+		return fmt.Errorf("expected KEY=VALUE, got %q", value)
+	}
+	*f.variables = append(*f.variables, variable)
+	return nil
+}
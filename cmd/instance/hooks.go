@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+
+	"light-launcher/internal/executor"
+	"light-launcher/internal/executor/builder"
+	"light-launcher/internal/types"
+)
+
+// hookFlag collects repeated hook flags, each holding one JSON encoded hook.
+type hookFlag struct {
+	hooks *[]types.HookCommand
+}
+
+func (f hookFlag) String() string {
+	if f.hooks == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d hooks", len(*f.hooks))
+}
+
+func (f hookFlag) Set(value string) error {
+	var hook types.HookCommand
+	if err := json.Unmarshal([]byte(value), &hook); err != nil {
+		return fmt.Errorf("invalid hook: %w", err)
+	}
+	*f.hooks = append(*f.hooks, hook)
+	return nil
+}
+
+// runPreLaunchHooks runs the pre-launch hooks in order. It returns an error
+// when a hook marked AbortOnFailure fails, which cancels the launch.
+func runPreLaunchHooks(opts types.LaunchOptions, logPath string) error {
+	session := executor.HookSession{Stage: executor.HookStagePreLaunch, Options: opts, LogPath: logPath, WinePrefix: builder.WinePrefix(opts)}
+	for index, hook := range opts.PreLaunchHooks {
+		log.Printf("--- PRE-LAUNCH HOOK %d: %s ---", index+1, hook.Command)
+		err := executor.RunHook(hook, session, hookOutput())
+		if err == nil {
+			continue
+		}
+		log.Printf("!!! Pre-launch hook %d failed: %v", index+1, err)
+		if hook.AbortOnFailure {
+			return fmt.Errorf("pre-launch hook %d failed: %w", index+1, err)
+		}
+	}
+	return nil
+}
+
+// runPostExitHooks runs every post-exit hook; failures are logged and skipped.
+func runPostExitHooks(opts types.LaunchOptions, logPath string, exitCode int) {
+	session := executor.HookSession{Stage: executor.HookStagePostExit, Options: opts, LogPath: logPath, WinePrefix: builder.WinePrefix(opts), ExitCode: exitCode}
+	for index, hook := range opts.PostExitHooks {
+		log.Printf("--- POST-EXIT HOOK %d: %s ---", index+1, hook.Command)
+		if err := executor.RunHook(hook, session, hookOutput()); err != nil {
+			log.Printf("!!! Post-exit hook %d failed: %v", index+1, err)
+		}
+	}
+}
+
+func hookOutput() *os.File {
+	if logFileHandle != nil {
+		return logFileHandle
+	}
+	return os.Stderr
+}
@@ -1,33 +1,104 @@
 package main
 
-import "light-launcher/internal/types"
+import (
+	"flag"
+	"os"
+
+	"light-launcher/internal/config"
+	"light-launcher/internal/executor"
+	"light-launcher/internal/types"
+)
 
 // buildLaunchOptions creates the launch options from command line flags
 func buildLaunchOptions() types.LaunchOptions {
 	return types.LaunchOptions{
-		GamePath:      gamePath,
-		LauncherPath:  launcherPath,
-		PrefixPath:    prefixPath,
-		ProtonPath:    protonPath,
+		ID:             gameID,
+		Name:           gameName,
+		GamePath:       gamePath,
+		LauncherPath:   launcherPath,
+		PrefixPath:     prefixPath,
+		ProtonPath:     protonPath,
+		Backend:        backend,
+		WinePath:       winePath,
+		CustomArgs:     customArgs,
+		UmuGameID:      umuGameID,
+		UmuStore:       umuStore,
+		Environment:    environment,
+		PreLaunchHooks: preLaunchHooks,
+		PostExitHooks:  postExitHooks,
 		Extras: types.ExtrasConfig{
 			EnableMangoHud: mango,
 			EnableGamemode: gamemode,
+			MangoHud:       mangoConfig,
 			Lsfg: types.LsfgConfig{
 				Enabled:    lsfg,
 				Multiplier: lsfgMult,
 				PerfMode:   lsfgPerf,
 				DllPath:    lsfgDllPath,
+				Gpu:        lsfgGpu,
+				FlowScale:  lsfgFlowScale,
+				Pacing:     lsfgPacing,
+				AllowFp16:  lsfgFp16,
 			},
 			Memory: types.MemoryConfig{
 				Enabled: memoryMin,
 				Value:   memoryMinValue,
 			},
+			Scope:      scopeConfig,
+			Scheduling: schedulingConfig,
+			GpuDevice:  gpuDevice,
+			Proton:     protonOptions,
 			Gamescope: types.GamescopeConfig{
-				Enabled:     gamescope,
-				Width:       gsW,
-				Height:      gsH,
-				RefreshRate: gsR,
+				Enabled:         gamescope,
+				Width:           gsW,
+				Height:          gsH,
+				RefreshRate:     gsR,
+				OutputWidth:     gsOutW,
+				OutputHeight:    gsOutH,
+				Fullscreen:      gsFullscreen,
+				Borderless:      gsBorderless,
+				Filter:          gsFilter,
+				Scaler:          gsScaler,
+				Sharpness:       gsSharpness,
+				FrameLimit:      gsFpsLimit,
+				HDR:             gsHdr,
+				ForceGrabCursor: gsGrabCursor,
+				AdaptiveSync:    gsAdaptiveSync,
+				MangoApp:        gsMangoApp,
 			},
 		},
 	}
 }
+
+// loadSavedGame fills the flags from a saved game config, then parses the
+// command line again so flags given explicitly still take precedence.
+// Repeatable flags append on every parse, so their lists are collected from
+// each parse separately and merged with the command line's entries last.
+func loadSavedGame(id string) error {
+	saved, err := config.LoadGameConfigByID("", id)
+	if err != nil {
+		return err
+	}
+
+	resetRepeatableFlags()
+	options := executor.ResolveGamePath(*saved)
+	if err := flag.CommandLine.Parse(executor.BuildInstanceManagerArgs(options, showLogs)); err != nil {
+		return err
+	}
+	savedEnvironment, savedPreLaunchHooks, savedPostExitHooks := environment, preLaunchHooks, postExitHooks
+
+	resetRepeatableFlags()
+	if err := flag.CommandLine.Parse(os.Args[1:]); err != nil {
+		return err
+	}
+	environment = append(savedEnvironment, environment...)
+	preLaunchHooks = append(savedPreLaunchHooks, preLaunchHooks...)
+	postExitHooks = append(savedPostExitHooks, postExitHooks...)
+	return nil
+}
+
+func resetRepeatableFlags() {
+	environment = nil
+	preLaunchHooks = nil
+	postExitHooks = nil
+}
@@ -8,13 +8,21 @@ import (
 	"sort"
 	"strings"
 	"time"
+
+	"light-launcher/internal/config"
+	"light-launcher/internal/executor"
+	"light-launcher/internal/executor/builder"
+	"light-launcher/internal/system"
+	"light-launcher/internal/types"
+	"light-launcher/lib/umu"
 )
 
 func getLogPath() string {
 	homeDir := os.ExpandEnv("$HOME")
 	logDir := filepath.Join(homeDir, "LightLauncher/logs")
 	os.MkdirAll(logDir, 0755)
-	cleanupLogs(logDir, 10)
+	cleanupLogs(logDir, ".log", 10)
+	cleanupLogs(logDir, "-debug.tar.gz", 5)
 	timestamp := time.Now().Format("20060102-150405")
 	exeName := filepath.Base(gamePath)
 	return filepath.Join(logDir, fmt.Sprintf("%s-%s.log", exeName, timestamp))
@@ -39,14 +47,15 @@ func trimLogFile(filePath string, maxLines int) error {
 	return os.WriteFile(filePath, []byte(trimmedData), 0666)
 }
 
-func cleanupLogs(dir string, keep int) {
+// cleanupLogs keeps the newest files in dir ending with suffix
+func cleanupLogs(dir, suffix string, keep int) {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
 		return
 	}
 	var files []os.FileInfo
 	for _, entry := range entries {
-		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".log") {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), suffix) {
 			info, err := entry.Info()
 			if err == nil {
 				files = append(files, info)
@@ -64,19 +73,31 @@ func cleanupLogs(dir string, keep int) {
 }
 
 // logGameStartup logs the command and enabled features
-func logGameStartup(cmdArgs []string) {
+func logGameStartup(cmdArgs []string, opts types.LaunchOptions, logPath string) {
 	log.Printf("--- EXECUTION START ---")
-	log.Printf("COMMAND: %s", strings.Join(cmdArgs, " "))
+	log.Printf("COMMAND: %s", builder.FormatCommandForDisplay(cmdArgs, opts))
 	log.Printf("ENABLED FEATURES:")
 
 	if mango {
 		log.Printf("  [+] MangoHud")
+		if mangoConfig.PerGame {
+			log.Printf("      Per-game config: %s", config.GetGameMangoHudConfigPath(opts.Name, opts.ID))
+		}
 	}
 	if gamemode {
 		log.Printf("  [+] GameMode")
 	}
 	if gamescope {
 		log.Printf("  [+] Gamescope (%sx%s@%s)", gsW, gsH, gsR)
+		if gsOutW != "" || gsOutH != "" {
+			log.Printf("      Output: %sx%s", gsOutW, gsOutH)
+		}
+		if gsFilter != "" {
+			log.Printf("      Filter: %s (sharpness: %s)", gsFilter, gsSharpness)
+		}
+		if gsFpsLimit != "" {
+			log.Printf("      Frame limit: %s", gsFpsLimit)
+		}
 	}
 	if lsfg {
 		log.Printf("  [+] LSFG-VK (x%s, PerfMode:%v)", lsfgMult, lsfgPerf)
@@ -84,6 +105,61 @@ func logGameStartup(cmdArgs []string) {
 	if memoryMin {
 		log.Printf("  [+] Memory Protection (Min: %s)", memoryMinValue)
 	}
+	if scopeConfig.Enabled {
+		log.Printf("  [+] Resource Limits (MemoryMax:%s MemoryHigh:%s CPUQuota:%s CPUWeight:%s IOWeight:%s AllowedCPUs:%s)",
+			scopeConfig.MemoryMax, scopeConfig.MemoryHigh, scopeConfig.CPUQuota, scopeConfig.CPUWeight, scopeConfig.IOWeight, scopeConfig.AllowedCPUs)
+	}
+	launchBackend := builder.GetBackend(opts)
+	log.Printf("  [+] Backend: %s (%s)", launchBackend.Name(), launchBackend.Runner(opts))
+	if launchBackend.Name() == builder.BackendUmu {
+		umuVariables := builder.UmuEnvironment(opts)
+		for _, variable := range umuVariables {
+			log.Printf("  [+] umu: %s=%s", variable[0], variable[1])
+		}
+		if len(umuVariables) == 0 {
+			log.Printf("  [!] No umu ID set, protonfixes run as %s", umu.DefaultGameID)
+		}
+	}
+	protonVariables, protonWarnings := builder.ProtonEnvironment(opts)
+	for _, variable := range protonVariables {
+		log.Printf("  [+] Proton: %s=%s", variable[0], variable[1])
+	}
+	for _, warning := range protonWarnings {
+		log.Printf("  [!] Proton: %s", warning)
+	}
+	if gpuDevice != "" {
+		if device, found := builder.SelectedGpu(opts); found {
+			log.Printf("  [+] GPU: %s (%s, %s)", device.Name, device.PciAddress, device.Driver)
+		} else {
+			log.Printf("  [!] GPU %s not found, using the default GPU", gpuDevice)
+		}
+	}
+	if schedulingConfig.Enabled {
+		log.Printf("  [+] Scheduling (CPUs:%s Nice:%s IONice:%s/%s Idle:%v)",
+			schedulingConfig.CpuSet, schedulingConfig.Nice, schedulingConfig.IoniceClass, schedulingConfig.IoniceLevel, schedulingConfig.IdlePriority)
+		for _, warning := range builder.SchedulingWarnings(schedulingConfig) {
+			log.Printf("  [!] Scheduling: %s", warning)
+		}
+	}
+	if (scopeConfig.Enabled || memoryMin) && !system.IsSystemdUserAvailable() {
+		log.Printf("  [!] systemd user manager not available, running without a scope")
+	}
+	if debugPreset != "" {
+		log.Printf("  [+] Debug logging (%s): %s", debugPreset, executor.GetDebugDirectory(logPath))
+	}
+	for _, hook := range preLaunchHooks {
+		log.Printf("  [hook] pre-launch: %s", hook.Command)
+	}
+	for _, hook := range postExitHooks {
+		log.Printf("  [hook] post-exit: %s", hook.Command)
+	}
+	for _, variable := range environment {
+		if variable.Unset {
+			log.Printf("  [env] unset %s", variable.Key)
+		} else {
+			log.Printf("  [env] %s=%s", variable.Key, variable.Value)
+		}
+	}
 
 	log.Printf("-----------------------")
 
@@ -92,3 +168,35 @@ func logGameStartup(cmdArgs []string) {
 		_ = logFileHandle.Sync()
 	}
 }
+
+// enableDebugLogging returns the environment of a debug launch and creates
+// the directory Proton and DXVK write their logs to.
+func enableDebugLogging(logPath string) ([]string, error) {
+	preset, found := executor.FindDebugPreset(debugPreset)
+	if !found {
+		return nil, fmt.Errorf("unknown debug preset %q", debugPreset)
+	}
+	directory := executor.GetDebugDirectory(logPath)
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create debug log directory: %w", err)
+	}
+	return executor.DebugEnvironment(preset, directory), nil
+}
+
+// debugBundleWait is how long a debug launch waits for the game's process
+// group to exit before bundling its logs.
+const debugBundleWait = 30 * time.Second
+
+// bundleDebugLogs packs the session log and the debug logs into one archive
+func bundleDebugLogs(logPath string) string {
+	if logFileHandle != nil {
+		_ = logFileHandle.Sync()
+	}
+	bundlePath, err := executor.BundleDebugLogs(logPath)
+	if err != nil {
+		log.Printf("!!! Failed to bundle debug logs: %v\n", err)
+		return ""
+	}
+	log.Printf("Debug logs bundled: %s\n", bundlePath)
+	return bundlePath
+}
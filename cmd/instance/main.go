@@ -2,19 +2,33 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"log"
 	"os"
 
+	"light-launcher/internal/executor/builder"
+	"light-launcher/internal/session"
+	"light-launcher/internal/types"
+
 	"github.com/getlantern/systray"
 )
 
 var (
+	// Saved game identity
+	gameID   string
+	gameName string
+
 	// Game and launcher paths
 	gamePath      string
 	launcherPath  string
 	prefixPath    string
 	protonPath    string
 	protonPattern string
+	backend       string
+	winePath      string
+	customArgs    string
+	umuGameID     string
+	umuStore      string
 
 	// Feature flags
 	mango     bool
@@ -26,53 +40,155 @@ var (
 	showLogs  bool
 
 	// Gamescope configuration
-	gsW string
-	gsH string
-	gsR string
+	gsW            string
+	gsH            string
+	gsR            string
+	gsOutW         string
+	gsOutH         string
+	gsFilter       string
+	gsScaler       string
+	gsSharpness    string
+	gsFpsLimit     string
+	gsFullscreen   bool
+	gsBorderless   bool
+	gsHdr          bool
+	gsGrabCursor   bool
+	gsAdaptiveSync bool
+	gsMangoApp     bool
+
+	// MangoHud configuration
+	mangoConfig types.MangoHudConfig
 
 	// LSFG configuration
-	lsfgMult    string
-	lsfgDllPath string
+	lsfgMult      string
+	lsfgDllPath   string
+	lsfgGpu       string
+	lsfgFlowScale string
+	lsfgPacing    string
+	lsfgFp16      bool
 
 	// Memory configuration
 	memoryMinValue string
 
+	// Resource limits of the game's systemd scope
+	scopeConfig types.ScopeConfig
+
+	// CPU affinity and priorities
+	schedulingConfig types.SchedulingConfig
+
+	// PCI address of the GPU to render on
+	gpuDevice string
+
+	// Proton runtime switches
+	protonOptions types.ProtonOptions
+
+	// Per-game environment, in command line order
+	environment []types.EnvironmentVariable
+
+	// Hooks run around the game process
+	preLaunchHooks []types.HookCommand
+	postExitHooks  []types.HookCommand
+
+	// Export mode
+	exportScriptPath string
+
+	// Wine/Proton debug preset, empty for a normal launch
+	debugPreset string
+
 	// Logging
 	logFileHandle *os.File
+
+	// Control socket of the running session
+	controlServer    *session.Server
+	activeController *sessionController
 )
 
 func main() {
+	flag.StringVar(&gameID, "game-id", "", "ID of a saved game config (loaded when --game is not given)")
+	flag.StringVar(&gameName, "name", "", "Display name of the game")
 	flag.StringVar(&gamePath, "game", "", "Path to the game executable")
 	flag.StringVar(&launcherPath, "launcher", "", "Path to the launcher executable")
 	flag.StringVar(&prefixPath, "prefix", "", "Path to the WINEPREFIX")
 	flag.StringVar(&protonPath, "proton-path", "", "Full path to the Proton tool")
 	flag.StringVar(&protonPattern, "proton-pattern", "", "Proton pattern for UMU")
+	flag.StringVar(&backend, "backend", "", "Launch backend (umu, proton, wine), umu when empty")
+	flag.StringVar(&winePath, "wine-path", "", "Wine binary used by the wine backend")
+	registerProtonFlags(&protonOptions)
+	flag.StringVar(&umuGameID, "umu-id", "", "umu GAMEID used to apply protonfixes (e.g. umu-1091500)")
+	flag.StringVar(&umuStore, "umu-store", "", "umu STORE of the game (steam, egs, gog, ...)")
+	flag.StringVar(&customArgs, "args", "", "Custom launch arguments (supports quoting and %command%)")
 	flag.BoolVar(&mango, "mango", false, "Enable MangoHud")
+	flag.Var(mangoHudFlag{config: &mangoConfig}, "mango-config", "JSON encoded per-game MangoHud settings")
 	flag.BoolVar(&gamemode, "gamemode", false, "Enable GameMode")
+	flag.StringVar(&gpuDevice, "gpu", "", "PCI address of the GPU to render on (e.g. 0000:01:00.0)")
 	flag.BoolVar(&gamescope, "gamescope", false, "Enable Gamescope")
 	flag.BoolVar(&lsfg, "lsfg", false, "Enable LSFG-VK")
 	flag.StringVar(&lsfgMult, "lsfg-mult", "2", "LSFG Multiplier")
 	flag.BoolVar(&lsfgPerf, "lsfg-perf", false, "Enable LSFG Performance Mode")
 	flag.StringVar(&lsfgDllPath, "lsfg-dll-path", "", "Path to Lossless.dll")
+	flag.StringVar(&lsfgGpu, "lsfg-gpu", "", "GPU used for LSFG frame generation")
+	flag.StringVar(&lsfgFlowScale, "lsfg-flow-scale", "", "LSFG flow scale")
+	flag.StringVar(&lsfgPacing, "lsfg-pacing", "", "LSFG frame pacing mode")
+	flag.BoolVar(&lsfgFp16, "lsfg-fp16", false, "Allow LSFG to use FP16")
 	flag.BoolVar(&memoryMin, "memory-min", false, "Enable Memory Protection (min RAM)")
 	flag.StringVar(&memoryMinValue, "memory-min-value", "", "Memory Protection Value (e.g. 4G)")
+	flag.Var(scopeFlag{config: &scopeConfig}, "scope", "JSON encoded resource limits for the game's systemd scope")
+	flag.Var(schedulingFlag{config: &schedulingConfig}, "scheduling", "JSON encoded CPU affinity, nice and ionice settings")
 	flag.StringVar(&gsW, "gs-w", "1920", "Width")
 	flag.StringVar(&gsH, "gs-h", "1080", "Height")
 	flag.StringVar(&gsR, "gs-r", "60", "Refresh Rate")
+	flag.StringVar(&gsOutW, "gs-out-w", "", "Gamescope output width")
+	flag.StringVar(&gsOutH, "gs-out-h", "", "Gamescope output height")
+	flag.StringVar(&gsFilter, "gs-filter", "", "Gamescope upscale filter (linear, nearest, fsr, nis, pixel)")
+	flag.StringVar(&gsScaler, "gs-scaler", "", "Gamescope scaler (auto, integer, fit, fill, stretch)")
+	flag.StringVar(&gsSharpness, "gs-sharpness", "", "Gamescope FSR/NIS sharpness (0-20)")
+	flag.StringVar(&gsFpsLimit, "gs-fps-limit", "", "Gamescope frame rate limit")
+	flag.BoolVar(&gsFullscreen, "gs-fullscreen", false, "Start gamescope fullscreen")
+	flag.BoolVar(&gsBorderless, "gs-borderless", false, "Start gamescope borderless")
+	flag.BoolVar(&gsHdr, "gs-hdr", false, "Enable gamescope HDR output")
+	flag.BoolVar(&gsGrabCursor, "gs-grab-cursor", false, "Force gamescope to grab the cursor")
+	flag.BoolVar(&gsAdaptiveSync, "gs-adaptive-sync", false, "Enable adaptive sync in gamescope")
+	flag.BoolVar(&gsMangoApp, "gs-mangoapp", false, "Run MangoHud through gamescope's mangoapp")
+	flag.Var(environmentFlag{variables: &environment}, "env", "Set an environment variable (KEY=VALUE, repeatable)")
+	flag.Var(environmentFlag{variables: &environment, unset: true}, "unset-env", "Unset an environment variable (repeatable)")
+	flag.Var(hookFlag{hooks: &preLaunchHooks}, "pre-launch-hook", "JSON encoded hook run before the game starts (repeatable)")
+	flag.Var(hookFlag{hooks: &postExitHooks}, "post-exit-hook", "JSON encoded hook run after the game exits (repeatable)")
 	flag.BoolVar(&showLogs, "logs", true, "Show terminal logs")
+	flag.StringVar(&debugPreset, "debug", "", "Launch with Proton/Wine debug logging (crash, graphics, input, audio, network)")
+	flag.StringVar(&exportScriptPath, "export-script", "", "Write a standalone launch script to this path and exit")
 	flag.Parse()
 
+	if gamePath == "" && gameID != "" {
+		if err := loadSavedGame(gameID); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to load game %s: %v\n", gameID, err)
+			os.Exit(1)
+		}
+	}
+
 	if gamePath == "" {
 		os.Exit(1)
 	}
 
+	if exportScriptPath != "" {
+		if err := builder.WriteLaunchScript(exportScriptPath, buildLaunchOptions()); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write launch script: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(exportScriptPath)
+		return
+	}
+
+	session.CleanStale()
+
 	logPath := getLogPath()
 	var err error
 	logFileHandle, err = os.OpenFile(logPath, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
 	if err == nil {
 		log.SetOutput(logFileHandle)
 		// Trim log file to last 500 lines to keep queue behavior
-		_ = trimLogFile(logPath, 500)
+		if debugPreset == "" {
+			_ = trimLogFile(logPath, 500)
+		}
 	}
 
 	systray.Run(func() { onReady(logPath) }, onExit)
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"light-launcher/internal/types"
+)
+
+// mangoHudFlag holds the JSON encoded per-game MangoHud settings.
+type mangoHudFlag struct {
+	config *types.MangoHudConfig
+}
+
+func (f mangoHudFlag) String() string {
+	if f.config == nil || !f.config.PerGame {
+		return ""
+	}
+	return "per-game"
+}
+
+func (f mangoHudFlag) Set(value string) error {
+	var cfg types.MangoHudConfig
+	if err := json.Unmarshal([]byte(value), &cfg); err != nil {
+		return fmt.Errorf("invalid MangoHud config: %w", err)
+	}
+	*f.config = cfg
+	return nil
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"flag"
+
+	"light-launcher/internal/types"
+)
+
+// registerProtonFlags adds one switch per Proton runtime option, writing into
+// options.
+func registerProtonFlags(options *types.ProtonOptions) {
+	flag.BoolVar(&options.DisableEsync, "proton-no-esync", false, "Disable esync (PROTON_NO_ESYNC)")
+	flag.BoolVar(&options.DisableFsync, "proton-no-fsync", false, "Disable fsync (PROTON_NO_FSYNC)")
+	flag.BoolVar(&options.EnableNtsync, "proton-ntsync", false, "Use NTSYNC (PROTON_USE_NTSYNC)")
+	flag.BoolVar(&options.UseWineD3D, "proton-wined3d", false, "Use WineD3D instead of DXVK (PROTON_USE_WINED3D)")
+	flag.BoolVar(&options.EnableWayland, "proton-wayland", false, "Use the Wayland driver (PROTON_ENABLE_WAYLAND)")
+	flag.BoolVar(&options.EnableNvapi, "proton-nvapi", false, "Enable NVAPI (PROTON_ENABLE_NVAPI)")
+	flag.BoolVar(&options.HideNvidiaGpu, "proton-hide-nvidia-gpu", false, "Report NVIDIA GPUs as AMD (PROTON_HIDE_NVIDIA_GPU)")
+	flag.BoolVar(&options.LargeAddressAware, "proton-large-address-aware", false, "Force large address aware (PROTON_FORCE_LARGE_ADDRESS_AWARE)")
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"light-launcher/internal/types"
+)
+
+// scopeFlag holds the JSON encoded resource limits of the game's scope.
+type scopeFlag struct {
+	config *types.ScopeConfig
+}
+
+func (f scopeFlag) String() string {
+	if f.config == nil || !f.config.Enabled {
+		return ""
+	}
+	return "enabled"
+}
+
+func (f scopeFlag) Set(value string) error {
+	var cfg types.ScopeConfig
+	if err := json.Unmarshal([]byte(value), &cfg); err != nil {
+		return fmt.Errorf("invalid scope config: %w", err)
+	}
+	*f.config = cfg
+	return nil
+}
+
+// schedulingFlag holds the JSON encoded CPU affinity and priority settings.
+type schedulingFlag struct {
+	config *types.SchedulingConfig
+}
+
+func (f schedulingFlag) String() string {
+	if f.config == nil || !f.config.Enabled {
+		return ""
+	}
+	return "enabled"
+}
+
+func (f schedulingFlag) Set(value string) error {
+	var cfg types.SchedulingConfig
+	if err := json.Unmarshal([]byte(value), &cfg); err != nil {
+		return fmt.Errorf("invalid scheduling config: %w", err)
+	}
+	*f.config = cfg
+	return nil
+}
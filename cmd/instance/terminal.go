@@ -26,10 +26,10 @@ func findTerminal() string {
 }
 
 // startLogTerminal opens a terminal window to display game logs
-func startLogTerminal(logPath string, gamePID int) {
+func startLogTerminal(logPath string, gamePID int) *exec.Cmd {
 	term := findTerminal()
 	if term == "" {
-		return
+		return nil
 	}
 
 	filterExpr := "setpriority|vk_xwayland_wait_ready|vk_wsi_force_swapchain"
@@ -45,5 +45,8 @@ func startLogTerminal(logPath string, gamePID int) {
 		cmd = exec.Command(term, "-e", "bash", "-c", tailCmd)
 	}
 
-	cmd.Start()
+	if err := cmd.Start(); err != nil {
+		return nil
+	}
+	return cmd
 }
@@ -8,12 +8,15 @@ import (
 	"os/exec"
 	"os/signal"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
 	"light-launcher/internal/executor"
 	"light-launcher/internal/executor/builder"
+	"light-launcher/internal/session"
+	"light-launcher/internal/types"
 	lsfgLib "light-launcher/lib/lsfg"
 
 	"github.com/getlantern/systray"
@@ -63,10 +66,85 @@ func onReady(logPath string) {
 	mKill := systray.AddMenuItem("End Process", "Stop this game")
 
 	// Start game
-	opts := buildLaunchOptions()
+	opts := builder.ResolveGpuDevice(buildLaunchOptions())
 	cmdArgs, env := builder.BuildCommand(opts)
+	env = append(env, session.EnvironmentVariable+"="+strconv.Itoa(os.Getpid()))
 
-	logGameStartup(cmdArgs)
+	logGameStartup(cmdArgs, opts, logPath)
+
+	if debugPreset != "" {
+		debugEnv, err := enableDebugLogging(logPath)
+		if err != nil {
+			log.Printf("!!! ERROR: %v\n", err)
+			sendNotification("Launch Error", exeNameClean+": "+err.Error())
+			systray.Quit()
+			return
+		}
+		env = append(env, debugEnv...)
+	}
+
+	if err := builder.ValidateBackend(opts); err != nil {
+		log.Printf("!!! ERROR: Launch backend unavailable: %v\n", err)
+		sendNotification("Launch Error", exeNameClean+": "+err.Error())
+		systray.Quit()
+		return
+	}
+
+	if err := builder.ValidateCustomArgs(opts.CustomArgs); err != nil {
+		log.Printf("!!! ERROR: %v\n", err)
+		sendNotification("Launch Error", exeNameClean+": "+err.Error())
+		systray.Quit()
+		return
+	}
+
+	if err := builder.ValidateGamescope(opts.Extras); err != nil {
+		log.Printf("!!! ERROR: Invalid gamescope settings: %v\n", err)
+		sendNotification("Launch Error", exeNameClean+": "+err.Error())
+		systray.Quit()
+		return
+	}
+
+	if err := builder.ValidateScheduling(opts.Extras); err != nil {
+		log.Printf("!!! ERROR: Invalid scheduling settings: %v\n", err)
+		sendNotification("Launch Error", exeNameClean+": "+err.Error())
+		systray.Quit()
+		return
+	}
+
+	if err := builder.ValidateScope(opts.Extras); err != nil {
+		log.Printf("!!! ERROR: Invalid resource limits: %v\n", err)
+		sendNotification("Launch Error", exeNameClean+": "+err.Error())
+		systray.Quit()
+		return
+	}
+
+	if err := builder.WriteMangoHudConfig(opts); err != nil {
+		log.Printf("!!! ERROR: %v\n", err)
+		sendNotification("Launch Error", exeNameClean+": "+err.Error())
+		systray.Quit()
+		return
+	}
+
+	if err := builder.WriteLsfgConfig(opts); err != nil {
+		log.Printf("!!! ERROR: %v\n", err)
+		sendNotification("Launch Error", exeNameClean+": "+err.Error())
+		systray.Quit()
+		return
+	}
+
+	if err := runPreLaunchHooks(opts, logPath); err != nil {
+		log.Printf("!!! ERROR: Launch aborted: %v\n", err)
+		sendNotification("Launch Aborted", exeNameClean+": "+err.Error())
+		systray.Quit()
+		return
+	}
+
+	if err := builder.GetBackend(opts).Prepare(opts); err != nil {
+		log.Printf("!!! ERROR: Failed to prepare the prefix: %v\n", err)
+		sendNotification("Launch Error", exeNameClean+": "+err.Error())
+		systray.Quit()
+		return
+	}
 
 	gameCmd := exec.Command(cmdArgs[0], cmdArgs[1:]...)
 	gameCmd.Env = env
@@ -77,15 +155,27 @@ func onReady(logPath string) {
 	if err := gameCmd.Start(); err != nil {
 		log.Printf("!!! ERROR: Failed to start game: %v\n", err)
 		sendNotification("Launch Error", "Failed to start "+exeNameClean+" ("+launcherName+"): "+err.Error())
+		runPostExitHooks(opts, logPath, executor.ExitCode(err))
 		systray.Quit()
 		return
 	}
 
+	// Control socket for the launcher UI
+	controller := newSessionController(opts, logPath, gameCmd.Process)
+	controller.register()
+	activeController = controller
+	if server, err := session.Listen(controller); err != nil {
+		log.Printf("Control socket unavailable: %v\n", err)
+	} else {
+		controlServer = server
+		log.Printf("Control socket: %s\n", server.Path())
+	}
+
 	// Internal helper to kill game gracefully
 	killGame := func() {
-		if gameCmd.Process != nil {
-			log.Println("Stopping game process group...")
-			executor.StopProcessGroup(gameCmd.Process)
+		log.Println("Stopping game process group...")
+		if _, err := controller.Stop(); err != nil {
+			log.Printf("!!! Failed to stop game: %v\n", err)
 		}
 	}
 
@@ -105,27 +195,56 @@ func onReady(logPath string) {
 		killGame()
 	}()
 
+	// Per-session resource usage, shown in the tray tooltip
+	go controller.monitorUsage(usageSampleInterval, func(usage types.SessionUsage) {
+		systray.SetTooltip("Running: " + exeNameClean + "\n" + formatUsage(usage))
+	})
+
 	// Show logs in terminal if enabled
 	if showLogs {
-		startLogTerminal(logPath, gameCmd.Process.Pid)
+		_, _ = controller.ToggleLogs()
 	}
 
-	// Periodically trim log file to keep it manageable (queue: last 500 lines)
-	go func() {
-		ticker := time.NewTicker(30 * time.Second)
-		defer ticker.Stop()
-		for range ticker.C {
-			_ = trimLogFile(logPath, 500)
-		}
-	}()
+	// Periodically trim log file to keep it manageable (queue: last 500 lines).
+	// Debug launches keep the full log for the bundle.
+	if debugPreset == "" {
+		go func() {
+			ticker := time.NewTicker(30 * time.Second)
+			defer ticker.Stop()
+			for range ticker.C {
+				_ = trimLogFile(logPath, 500)
+			}
+		}()
+	}
 
 	// Wait for game to exit
 	go func() {
 		err := gameCmd.Wait()
 		log.Printf("Game process exited with: %v\n", err)
+		controller.markExited()
+		if controller.wasStopped() {
+			log.Println("Game was stopped on request, skipping crash diagnosis")
+			controller.recordPlaySession(opts, executor.ExitCode(err), false)
+		} else {
+			report := diagnoseExit(opts, logPath, err)
+			controller.recordPlaySession(opts, executor.ExitCode(err), report.Crashed)
+
+			if report.Crashed {
+				sendNotification("Game Crashed", crashNotificationMessage(exeNameClean, report))
+			}
+		}
 
-		if err != nil {
-			sendNotification("Process Exited", fmt.Sprintf("%s exited with error: %v", exeNameClean, err))
+		runPostExitHooks(opts, logPath, executor.ExitCode(err))
+		controller.waitForStop()
+
+		if debugPreset != "" {
+			// Processes left in the game's group may still be writing logs.
+			if !executor.WaitForProcessGroup(gameCmd.Process.Pid, debugBundleWait) {
+				log.Println("Game processes still running, bundling the debug logs written so far")
+			}
+			if bundlePath := bundleDebugLogs(logPath); bundlePath != "" {
+				sendNotification("Debug Logs Saved", filepath.Base(bundlePath))
+			}
 		}
 
 		time.Sleep(1 * time.Second)
@@ -144,7 +263,7 @@ func setupLsfgMenu() {
 			<-mLsfgEdit.ClickedCh
 			log.Printf("LSFG menu handler: click received!")
 
-			profile, idx, err := lsfgLib.FindProfileForGame(gamePath)
+			profile, idx, err := lsfgLib.FindProfileForGameAtPath(gamePath, builder.GetLsfgConfigPath(buildLaunchOptions()))
 			if err != nil {
 				log.Printf("LSFG menu handler: error finding profile: %v", err)
 				sendNotification("LSFG-VK Config", fmt.Sprintf("Could not find profile for this game: %v", err))
@@ -190,6 +309,12 @@ func launchLsfgUI() {
 }
 
 func onExit() {
+	if controlServer != nil {
+		_ = controlServer.Close()
+	}
+	if activeController != nil {
+		activeController.unregister()
+	}
 	if logFileHandle != nil {
 		logFileHandle.Close()
 	}
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 
 	"light-launcher/internal/config"
+	"light-launcher/internal/session"
 	"light-launcher/internal/system"
 	"light-launcher/internal/types"
 
@@ -45,13 +46,25 @@ func (app *App) CloseWindow() {
 }
 
 func (app *App) GetExeIcon(executablePath string) string {
-	if _, err := os.Stat(executablePath); os.IsNotExist(err) {
+	data := extractExeIcon(executablePath)
+	if len(data) == 0 {
 		return ""
 	}
+	return "data:image/x-icon;base64," + base64.StdEncoding.EncodeToString(data)
+}
+
+func extractExeIcon(executablePath string) []byte {
+	if _, err := os.Stat(executablePath); os.IsNotExist(err) {
+		return nil
+	}
+	// Only Windows executables carry icon resources.
+	if system.GetExecutableKind(executablePath) != system.ExecutableWindows {
+		return nil
+	}
 
 	temporaryDirectory, err := os.MkdirTemp("", "light-launcher-icon-*")
 	if err != nil {
-		return ""
+		return nil
 	}
 	defer os.RemoveAll(temporaryDirectory)
 
@@ -59,7 +72,7 @@ func (app *App) GetExeIcon(executablePath string) string {
 	if err := wrestoolCommand.Run(); err == nil {
 		matches, _ := filepath.Glob(filepath.Join(temporaryDirectory, "*.ico"))
 		if len(matches) > 0 {
-			if icon := tryReadIcon(matches[0]); icon != "" {
+			if icon := tryReadIcon(matches[0]); icon != nil {
 				return icon
 			}
 		}
@@ -67,7 +80,7 @@ func (app *App) GetExeIcon(executablePath string) string {
 
 	icoextractCommand := exec.Command("icoextract", executablePath, filepath.Join(temporaryDirectory, "icon.ico"))
 	if err := icoextractCommand.Run(); err == nil {
-		if icon := tryReadIcon(filepath.Join(temporaryDirectory, "icon.ico")); icon != "" {
+		if icon := tryReadIcon(filepath.Join(temporaryDirectory, "icon.ico")); icon != nil {
 			return icon
 		}
 		matches, _ := filepath.Glob(filepath.Join(temporaryDirectory, "*.ico"))
@@ -76,15 +89,15 @@ func (app *App) GetExeIcon(executablePath string) string {
 		}
 	}
 
-	return ""
+	return nil
 }
 
-func tryReadIcon(iconPath string) string {
+func tryReadIcon(iconPath string) []byte {
 	data, err := os.ReadFile(iconPath)
 	if err != nil || len(data) == 0 {
-		return ""
+		return nil
 	}
-	return "data:image/x-icon;base64," + base64.StdEncoding.EncodeToString(data)
+	return data
 }
 
 func (app *App) GetSystemToolsStatus() types.SystemToolsStatus {
@@ -99,6 +112,11 @@ func (app *App) GetSystemUsage() types.SystemUsage {
 	return system.GetSystemUsage()
 }
 
+// GetGpuDevices lists the GPUs a game can be pinned to.
+func (app *App) GetGpuDevices() []types.GpuDevice {
+	return system.GetGpuDevices()
+}
+
 func (app *App) GetShaderCacheSize() string {
 	return system.GetShaderCacheSize()
 }
@@ -115,19 +133,17 @@ func (app *App) ClearSwap() error {
 	return system.ClearSwap()
 }
 
-func (app *App) CleanupProcesses() error {
-	commands := []string{
-		"umu-run",
-		"pressure-vessel",
-		"gamescopereaper",
-		"steam-runtime-launcher-service",
-		"srt-bwrap",
-		"reaper",
-	}
-	for _, command := range commands {
-		_ = exec.Command("pkill", "-f", command).Run()
-	}
-	return nil
+// FindCleanupProcesses lists what is left of LightLauncher sessions, or with
+// a prefixPath, everything running in that prefix, for the user to confirm.
+func (app *App) FindCleanupProcesses(prefixPath string) ([]types.CleanupProcess, error) {
+	return session.FindCleanupProcesses(config.ExpandPath(prefixPath)), nil
+}
+
+// CleanupProcesses kills the processes the user confirmed from
+// FindCleanupProcesses, skipping any that exited or whose pid was reused.
+func (app *App) CleanupProcesses(processes []types.CleanupProcess) (*types.CleanupResult, error) {
+	result := session.Cleanup(processes)
+	return &result, nil
 }
 
 func (app *App) GetTotalRam() (int, error) {
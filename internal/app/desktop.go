@@ -0,0 +1,131 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"light-launcher/internal/config"
+	"light-launcher/internal/desktop"
+	"light-launcher/internal/types"
+)
+
+func (app *App) CreateDesktopEntry(executablePath string) error {
+	cfg, err := app.GetConfig(executablePath)
+	if err != nil {
+		return fmt.Errorf("could not find game: %w", err)
+	}
+	return writeDesktopEntry(*cfg)
+}
+
+func (app *App) RemoveDesktopEntry(executablePath string) error {
+	cfg, err := app.GetConfig(executablePath)
+	if err != nil {
+		return fmt.Errorf("could not find game: %w", err)
+	}
+	return removeDesktopEntry(cfg.ID)
+}
+
+func (app *App) HasDesktopEntry(executablePath string) bool {
+	cfg, err := app.GetConfig(executablePath)
+	if err != nil || cfg.ID == "" {
+		return false
+	}
+	_, err = os.Stat(desktop.GetEntryPath(cfg.ID))
+	return err == nil
+}
+
+// SyncDesktopEntries writes an entry for every saved game and removes entries
+// whose game no longer exists.
+func (app *App) SyncDesktopEntries() error {
+	configs, err := config.ListGameConfigs()
+	if err != nil {
+		return err
+	}
+
+	var firstError error
+	known := make(map[string]bool)
+	for _, gameConfig := range configs {
+		if gameConfig.ID == "" {
+			continue
+		}
+		known[gameConfig.ID] = true
+		if err := writeDesktopEntry(gameConfig); err != nil && firstError == nil {
+			firstError = err
+		}
+	}
+
+	ids, err := desktop.ListEntryIDs()
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if !known[id] {
+			if err := removeDesktopEntry(id); err != nil && firstError == nil {
+				firstError = err
+			}
+		}
+	}
+	return firstError
+}
+
+func writeDesktopEntry(options types.LaunchOptions) error {
+	if options.ID == "" {
+		return fmt.Errorf("%s has no game ID, save it again before adding it to the menu", options.Name)
+	}
+
+	instanceManagerPath := findInstanceManager()
+	if instanceManagerPath == "" {
+		return fmt.Errorf("instance manager not found")
+	}
+
+	iconSource := options.LauncherPath
+	if iconSource == "" {
+		iconSource = options.GamePath
+	}
+	iconPath := config.GetGameIconPath(options.ID)
+	if err := saveIconAsPng(iconSource, iconPath); err != nil {
+		iconPath = ""
+	}
+
+	return desktop.WriteEntry(desktop.Entry{
+		ID:       options.ID,
+		Name:     gameDisplayName(options),
+		Exec:     []string{instanceManagerPath, "--game-id", options.ID, "--logs=false"},
+		IconPath: iconPath,
+	})
+}
+
+func removeDesktopEntry(id string) error {
+	if id == "" {
+		return nil
+	}
+	_ = os.Remove(config.GetGameIconPath(id))
+	return desktop.RemoveEntry(id)
+}
+
+func saveIconAsPng(executablePath, iconPath string) error {
+	data := extractExeIcon(executablePath)
+	if len(data) == 0 {
+		return fmt.Errorf("no icon found in %s", executablePath)
+	}
+
+	pngData, err := desktop.ConvertIcoToPng(data)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(iconPath), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(iconPath, pngData, 0644)
+}
+
+func gameDisplayName(options types.LaunchOptions) string {
+	if options.Name != "" {
+		return options.Name
+	}
+	name := filepath.Base(options.GamePath)
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
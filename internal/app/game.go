@@ -5,61 +5,78 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"strconv"
 	"strings"
+	"time"
 
 	"light-launcher/internal/config"
+	"light-launcher/internal/desktop"
 	"light-launcher/internal/executor"
 	"light-launcher/internal/executor/builder"
-	"light-launcher/internal/system"
 	"light-launcher/internal/types"
 	"light-launcher/lib/lsfg"
 )
 
 func (app *App) RunGame(options types.LaunchOptions, showLogs bool) error {
 	executor.DebugLog("RunGame called with options for: " + options.GamePath)
+	return app.launchInstance(options, showLogs)
+}
 
-	if !options.UseGamePath && options.LauncherPath != "" {
-		options.GamePath = options.LauncherPath
+// RunGameDebug launches the game with Proton/Wine debug logging. The Proton,
+// DXVK and vkd3d logs are bundled with the session log when the game exits.
+func (app *App) RunGameDebug(options types.LaunchOptions, showLogs bool, preset string) error {
+	executor.DebugLog("RunGameDebug called with preset " + preset + " for: " + options.GamePath)
+	if _, found := executor.FindDebugPreset(preset); !found {
+		return fmt.Errorf("unknown debug preset: %s", preset)
 	}
+	return app.launchInstance(options, showLogs, "--debug", preset)
+}
+
+// GetLaunchBackends lists the backends a game can be launched with.
+func (app *App) GetLaunchBackends() []string {
+	return builder.BackendNames()
+}
+
+// GetDebugPresets lists the debug logging presets RunGameDebug accepts.
+func (app *App) GetDebugPresets() []string {
+	presets := make([]string, 0, len(executor.DebugPresets))
+	for _, preset := range executor.DebugPresets {
+		presets = append(presets, preset.Name)
+	}
+	return presets
+}
+
+func (app *App) launchInstance(options types.LaunchOptions, showLogs bool, extraArguments ...string) error {
+	options = executor.ResolveGamePath(options)
 
 	if _, err := os.Stat(options.GamePath); os.IsNotExist(err) {
 		return fmt.Errorf("game executable not found at: %s", options.GamePath)
 	}
 
-	_ = config.SaveGameConfig(options)
+	if err := builder.ValidateBackend(options); err != nil {
+		return err
+	}
 
-	if options.Extras.Lsfg.Enabled {
-		configPath, err := lsfg.GetConfigPath()
-		if err == nil {
-			// Always update/save profile during launch if enabled
-			gpu := options.Extras.Lsfg.Gpu
-			if gpu == "" {
-				gpuList := system.GetListGpus()
-				if len(gpuList) > 0 {
-					gpu = gpuList[0]
-				}
-			}
+	if err := builder.ValidateGamescope(options.Extras); err != nil {
+		return err
+	}
 
-			_ = lsfg.SaveProfileToPath(options.Name, options.GamePath, configPath,
-				parseMultiplier(options.Extras.Lsfg.Multiplier),
-				options.Extras.Lsfg.PerfMode,
-				options.Extras.Lsfg.DllPath,
-				gpu,
-				options.Extras.Lsfg.FlowScale,
-				options.Extras.Lsfg.Pacing,
-				options.Extras.Lsfg.AllowFp16)
-		}
-	} else {
-		_ = lsfg.DisableProfileInConfig(options.Name, options.GamePath)
+	if err := builder.ValidateCustomArgs(options.CustomArgs); err != nil {
+		return err
 	}
 
+	// The instance manager derives per-game files such as MangoHud.conf from
+	// the ID, so it has to match the one the config is saved under.
+	if options.ID == "" {
+		options.ID = config.GenerateID()
+	}
+	_ = config.SaveGameConfig(options)
+
 	instanceManagerPath := findInstanceManager()
 	if instanceManagerPath == "" {
 		return fmt.Errorf("instance manager not found")
 	}
 
-	arguments := buildInstanceManagerArgs(options, showLogs)
+	arguments := append(executor.BuildInstanceManagerArgs(options, showLogs), extraArguments...)
 	command := exec.Command(instanceManagerPath, arguments...)
 	if err := command.Start(); err != nil {
 		return fmt.Errorf("failed to start instance manager: %w", err)
@@ -97,47 +114,6 @@ func findInstanceManager() string {
 	return ""
 }
 
-func buildInstanceManagerArgs(options types.LaunchOptions, showLogs bool) []string {
-	arguments := []string{
-		"--game", options.GamePath,
-		"--launcher", options.LauncherPath,
-		"--prefix", options.PrefixPath,
-		"--proton-pattern", filepath.Base(options.ProtonPath),
-		"--proton-path", options.ProtonPath,
-	}
-	if options.Extras.EnableMangoHud {
-		arguments = append(arguments, "--mango")
-	}
-	if options.Extras.EnableGamemode {
-		arguments = append(arguments, "--gamemode")
-	}
-	if options.Extras.Lsfg.Enabled {
-		arguments = append(arguments, "--lsfg", "--lsfg-mult", options.Extras.Lsfg.Multiplier)
-		if options.Extras.Lsfg.PerfMode {
-			arguments = append(arguments, "--lsfg-perf")
-		}
-		if options.Extras.Lsfg.DllPath != "" {
-			arguments = append(arguments, "--lsfg-dll-path", options.Extras.Lsfg.DllPath)
-		}
-	}
-	if options.Extras.Memory.Enabled {
-		arguments = append(arguments, "--memory-min")
-		if options.Extras.Memory.Value != "" {
-			arguments = append(arguments, "--memory-min-value", options.Extras.Memory.Value)
-		}
-	}
-	if options.Extras.Gamescope.Enabled {
-		arguments = append(arguments, "--gamescope",
-			"--gs-w", options.Extras.Gamescope.Width,
-			"--gs-h", options.Extras.Gamescope.Height,
-			"--gs-r", options.Extras.Gamescope.RefreshRate)
-	}
-	if !showLogs {
-		arguments = append(arguments, "--logs=false")
-	}
-	return arguments
-}
-
 func (app *App) GetAllGames() ([]types.GameInfo, error) {
 	configs, err := config.ListGameConfigs()
 	if err != nil {
@@ -160,75 +136,21 @@ func (app *App) GetAllGames() ([]types.GameInfo, error) {
 			cleanedPath = absolutePath
 		}
 
+		history, _ := config.LoadPlayHistory(gameConfig.Name, gameConfig.ID)
+		summary := config.SummarizePlayHistory(history)
+
 		games = append(games, types.GameInfo{
-			Name:   name,
-			Path:   cleanedPath,
-			Config: gameConfig,
+			Name:          name,
+			Path:          cleanedPath,
+			Config:        gameConfig,
+			IsRecent:      summary.LastPlayed > 0 && time.Since(time.Unix(summary.LastPlayed, 0)) < config.RecentPlayWindow,
+			LastPlayed:    summary.LastPlayed,
+			TotalPlaytime: summary.TotalSeconds,
 		})
 	}
 	return games, nil
 }
 
-func (app *App) GetRunningSessions() ([]types.RunningSession, error) {
-	output, _ := exec.Command("pgrep", "light-launcher-instance").Output()
-	if len(output) == 0 {
-		output, _ = exec.Command("pgrep", "light-launcher-instan").Output()
-	}
-
-	pids := strings.Split(strings.TrimSpace(string(output)), "\n")
-	sessions := make([]types.RunningSession, 0)
-
-	for _, pidString := range pids {
-		if pidString == "" {
-			continue
-		}
-		pid, err := strconv.Atoi(pidString)
-		if err != nil {
-			continue
-		}
-
-		cmdlinePath := fmt.Sprintf("/proc/%d/cmdline", pid)
-		content, err := os.ReadFile(cmdlinePath)
-		if err != nil {
-			continue
-		}
-
-		arguments := strings.Split(string(content), "\x00")
-		gamePath := ""
-		for index, argument := range arguments {
-			if argument == "--game" && index+1 < len(arguments) {
-				gamePath = arguments[index+1]
-				break
-			}
-		}
-
-		if gamePath != "" {
-			name := filepath.Base(gamePath)
-			name = strings.TrimSuffix(name, filepath.Ext(name))
-
-			cleanedPath := filepath.Clean(gamePath)
-			if absolutePath, err := filepath.Abs(cleanedPath); err == nil {
-				cleanedPath = absolutePath
-			}
-
-			sessions = append(sessions, types.RunningSession{
-				Pid:      pid,
-				GamePath: cleanedPath,
-				GameName: name,
-			})
-		}
-	}
-	return sessions, nil
-}
-
-func (app *App) KillSession(pid int) error {
-	process, err := os.FindProcess(pid)
-	if err != nil {
-		return err
-	}
-	return process.Signal(os.Interrupt)
-}
-
 func (app *App) RemoveGame(executablePath string) error {
 	cfg, err := app.GetConfig(executablePath)
 	if err != nil {
@@ -244,6 +166,7 @@ func (app *App) RemoveGame(executablePath string) error {
 	}
 
 	_ = lsfg.DisableProfileInConfig(cfg.Name, executablePath)
+	_ = removeDesktopEntry(cfg.ID)
 	return nil
 }
 
@@ -259,6 +182,22 @@ func (app *App) RunPrefixTool(prefixPath, toolName, protonPath string) error {
 	return command.Start()
 }
 
+func (app *App) ExportLaunchScript(executablePath, outputPath string) (string, error) {
+	cfg, err := app.GetConfig(executablePath)
+	if err != nil {
+		return "", fmt.Errorf("could not find game to export: %w", err)
+	}
+
+	if outputPath == "" {
+		outputPath = config.GetLaunchScriptPath(cfg.Name, cfg.ID)
+	}
+
+	if err := builder.WriteLaunchScript(outputPath, executor.ResolveGamePath(*cfg)); err != nil {
+		return "", fmt.Errorf("failed to write launch script: %w", err)
+	}
+	return outputPath, nil
+}
+
 func (app *App) GetConfig(executablePath string) (*types.LaunchOptions, error) {
 	return config.LoadGameConfig(executablePath)
 }
@@ -272,15 +211,13 @@ func (app *App) LoadPrefixConfig(prefixName string) (*types.LaunchOptions, error
 }
 
 func (app *App) SaveGameConfig(options types.LaunchOptions) error {
-	return config.SaveGameConfig(options)
-}
-
-func parseMultiplier(multiplier string) int {
-	value := 2
-	if multiplier != "" {
-		if _, err := fmt.Sscanf(multiplier, "%d", &value); err != nil {
-			value = 2
+	if err := config.SaveGameConfig(options); err != nil {
+		return err
+	}
+	if options.ID != "" {
+		if _, err := os.Stat(desktop.GetEntryPath(options.ID)); err == nil {
+			_ = writeDesktopEntry(options)
 		}
 	}
-	return value
+	return nil
 }
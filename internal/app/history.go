@@ -0,0 +1,52 @@
+package app
+
+import (
+	"light-launcher/internal/config"
+	"light-launcher/internal/types"
+)
+
+func (app *App) GetPlaytime(executablePath string) (*types.PlaytimeSummary, error) {
+	cfg, err := app.GetConfig(executablePath)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := config.LoadPlayHistory(cfg.Name, cfg.ID)
+	if err != nil {
+		return nil, err
+	}
+	summary := config.SummarizePlayHistory(history)
+	return &summary, nil
+}
+
+// GetRecentSessions returns up to limit of the game's sessions, newest first.
+func (app *App) GetRecentSessions(executablePath string, limit int) ([]types.PlaySession, error) {
+	cfg, err := app.GetConfig(executablePath)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := config.LoadPlayHistory(cfg.Name, cfg.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	recent := make([]types.PlaySession, 0, len(history))
+	for index := len(history) - 1; index >= 0; index-- {
+		if limit > 0 && len(recent) == limit {
+			break
+		}
+		recent = append(recent, history[index])
+	}
+	return recent, nil
+}
+
+// GetLastCrashReport returns the report from the game's most recent crash,
+// or nil if it has not crashed.
+func (app *App) GetLastCrashReport(executablePath string) (*types.CrashReport, error) {
+	cfg, err := app.GetConfig(executablePath)
+	if err != nil {
+		return nil, err
+	}
+	return config.LoadCrashReport(cfg.Name, cfg.ID)
+}
@@ -3,13 +3,15 @@ package app
 import (
 	"os"
 	"path/filepath"
+	"strconv"
 
+	"light-launcher/internal/config"
 	"light-launcher/internal/executor"
+	"light-launcher/internal/executor/builder"
 	"light-launcher/internal/system"
 	"light-launcher/internal/types"
 	"light-launcher/lib/lsfg"
 
-	"github.com/pelletier/go-toml/v2"
 	"github.com/wailsapp/wails/v3/pkg/application"
 )
 
@@ -21,23 +23,17 @@ func (app *App) GetUtilsStatus() types.UtilsStatus {
 }
 
 func (app *App) GetLsfgProfileForGame(name, gamePath string) (*types.LsfgProfileData, error) {
-	profile, _, err := lsfg.FindProfileForGame(gamePath)
+	configPath := app.lsfgConfigPathForGame(gamePath)
+	profile, _, err := lsfg.FindProfileForGameAtPath(gamePath, configPath)
 	if err != nil {
 		return nil, nil
 	}
 
 	var dllPath string
 	var allowFp16 bool
-	configPath, err := lsfg.GetConfigPath()
-	if err == nil {
-		data, err := os.ReadFile(configPath)
-		if err == nil {
-			var configFile lsfg.ConfigFile
-			if err := toml.Unmarshal(data, &configFile); err == nil {
-				dllPath = configFile.Global.DLL
-				allowFp16 = configFile.Global.AllowFP16
-			}
-		}
+	if configFile, err := lsfg.ReadConfigFile(configPath); err == nil {
+		dllPath = configFile.Global.DLL
+		allowFp16 = configFile.Global.AllowFP16
 	}
 
 	return &types.LsfgProfileData{
@@ -52,6 +48,19 @@ func (app *App) GetLsfgProfileForGame(name, gamePath string) (*types.LsfgProfile
 	}, nil
 }
 
+// lsfgConfigPathForGame returns the per-game lsfg-vk config of a saved game,
+// falling back to the shared conf.toml for games launched before it existed.
+func (app *App) lsfgConfigPathForGame(gamePath string) string {
+	if cfg, err := app.GetConfig(gamePath); err == nil {
+		configPath := builder.GetLsfgConfigPath(*cfg)
+		if _, err := os.Stat(configPath); err == nil {
+			return configPath
+		}
+	}
+	configPath, _ := lsfg.GetConfigPath()
+	return configPath
+}
+
 func (app *App) InstallLsfg() error {
 	return lsfg.Install(func(percent int, message string) {
 		application.Get().Event.Emit("lsfg-install-progress", map[string]interface{}{
@@ -84,6 +93,8 @@ func (app *App) UninstallLsfg() error {
 	return lsfg.Uninstall(executor.DebugLog)
 }
 
+// SaveLsfgProfile updates the running game's lsfg-vk config, which the layer
+// reloads live, and keeps the saved game config in sync for future launches.
 func (app *App) SaveLsfgProfile(profileName, gamePath string, multiplier int, performanceMode bool, dllPath, gpu, flowScale, pacing string, allowFp16 bool) error {
 	cfg, err := app.GetConfig(gamePath)
 	if err != nil {
@@ -98,12 +109,19 @@ func (app *App) SaveLsfgProfile(profileName, gamePath string, multiplier int, pe
 		}
 	}
 
-	configPath, err := lsfg.GetConfigPath()
-	if err != nil {
+	cfg.Extras.Lsfg.Enabled = true
+	cfg.Extras.Lsfg.Multiplier = strconv.Itoa(multiplier)
+	cfg.Extras.Lsfg.PerfMode = performanceMode
+	cfg.Extras.Lsfg.DllPath = dllPath
+	cfg.Extras.Lsfg.Gpu = gpu
+	cfg.Extras.Lsfg.FlowScale = flowScale
+	cfg.Extras.Lsfg.Pacing = pacing
+	cfg.Extras.Lsfg.AllowFp16 = allowFp16
+	if err := config.SaveGameConfig(*cfg); err != nil {
 		return err
 	}
 
-	return lsfg.SaveProfileToPath(cfg.ID, gamePath, configPath, multiplier, performanceMode, dllPath, gpu, flowScale, pacing, allowFp16)
+	return lsfg.SaveProfileToPath(cfg.ID, gamePath, builder.GetLsfgConfigPath(*cfg), multiplier, performanceMode, dllPath, gpu, flowScale, pacing, allowFp16)
 }
 
 func (app *App) DisableLsfgProfile(profileName, gamePath string) error {
@@ -111,7 +129,12 @@ func (app *App) DisableLsfgProfile(profileName, gamePath string) error {
 	if err != nil {
 		return err
 	}
-	return lsfg.DisableProfileInConfig(cfg.ID, gamePath)
+
+	cfg.Extras.Lsfg.Enabled = false
+	if err := config.SaveGameConfig(*cfg); err != nil {
+		return err
+	}
+	return lsfg.DisableProfileAtPath(cfg.ID, gamePath, builder.GetLsfgConfigPath(*cfg))
 }
 
 func (app *App) RemoveProfile(mainExecutablePath string) error {
@@ -0,0 +1,42 @@
+package app
+
+import (
+	"light-launcher/internal/config"
+	"light-launcher/internal/types"
+	"light-launcher/lib/mangohud"
+)
+
+// LoadMangoHudConfig reads an existing MangoHud config to seed per-game
+// settings. An empty path reads the user's global MangoHud.conf.
+func (app *App) LoadMangoHudConfig(path string) (*types.MangoHudConfig, error) {
+	if path == "" {
+		globalPath, err := mangohud.GetGlobalConfigPath()
+		if err != nil {
+			return nil, err
+		}
+		path = globalPath
+	}
+	loaded, err := mangohud.Load(config.ExpandPath(path))
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.MangoHudConfig{
+		PerGame:        true,
+		Preset:         loaded.Preset,
+		Position:       loaded.Position,
+		FpsLimit:       loaded.FpsLimit,
+		ShowFps:        loaded.ShowFps,
+		ShowFrametime:  loaded.ShowFrametime,
+		ShowCpu:        loaded.ShowCpu,
+		ShowGpu:        loaded.ShowGpu,
+		ShowCpuTemp:    loaded.ShowCpuTemp,
+		ShowGpuTemp:    loaded.ShowGpuTemp,
+		ShowRam:        loaded.ShowRam,
+		ShowVram:       loaded.ShowVram,
+		ShowBattery:    loaded.ShowBattery,
+		LogFolder:      loaded.LogFolder,
+		ExtraLines:     loaded.ExtraLines,
+		ChangedToggles: loaded.Toggled,
+	}, nil
+}
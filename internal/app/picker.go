@@ -7,6 +7,9 @@ import (
 	"regexp"
 	"strings"
 
+	"light-launcher/internal/config"
+	"light-launcher/internal/system"
+
 	"github.com/wailsapp/wails/v3/pkg/application"
 )
 
@@ -107,7 +110,7 @@ func (app *App) SearchExecutables(folderPath string, maxDepth int, excludeNames
 			}
 		}
 
-		if strings.EqualFold(filepath.Ext(path), ".exe") {
+		if strings.EqualFold(filepath.Ext(path), ".exe") || isNativeGameCandidate(path) {
 			executables = append(executables, path)
 		}
 
@@ -117,9 +120,29 @@ func (app *App) SearchExecutables(folderPath string, maxDepth int, excludeNames
 	return executables, err
 }
 
+// isNativeGameCandidate matches Linux binaries, AppImages and scripts a game
+// could be started from, skipping shared libraries.
+func isNativeGameCandidate(path string) bool {
+	name := strings.ToLower(filepath.Base(path))
+	if strings.HasSuffix(name, ".so") || strings.Contains(name, ".so.") {
+		return false
+	}
+	if !system.IsExecutableFile(path) && !strings.HasSuffix(name, ".sh") && !strings.HasSuffix(name, ".appimage") {
+		return false
+	}
+	return system.IsNativeExecutable(path)
+}
+
+// GetExecutableKind reports whether path is a Windows executable, a Linux
+// binary, an AppImage or a script.
+func (app *App) GetExecutableKind(path string) string {
+	return system.GetExecutableKind(config.ExpandPath(path))
+}
+
 func (app *App) PickFile() (string, error) {
 	if path, ok := app.runSystemPicker("Select Game Executable", false, []application.FileFilter{
 		{DisplayName: "Executables (*.exe)", Pattern: "*.exe"},
+		{DisplayName: "Linux Games (*.AppImage, *.sh, *.x86_64)", Pattern: "*.AppImage;*.sh;*.x86_64"},
 		{DisplayName: "All Files", Pattern: "*.*"},
 	}); ok {
 		return path, nil
@@ -129,6 +152,7 @@ func (app *App) PickFile() (string, error) {
 		Title: "Select Game Executable",
 		Filters: []application.FileFilter{
 			{DisplayName: "Executables (*.exe)", Pattern: "*.exe"},
+			{DisplayName: "Linux Games (*.AppImage, *.sh, *.x86_64)", Pattern: "*.AppImage;*.sh;*.x86_64"},
 			{DisplayName: "All Files", Pattern: "*.*"},
 		},
 	}).PromptForSingleSelection()
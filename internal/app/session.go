@@ -0,0 +1,112 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"light-launcher/internal/config"
+	"light-launcher/internal/executor"
+	"light-launcher/internal/session"
+	"light-launcher/internal/types"
+)
+
+// GetRunningSessions returns the registered sessions, refreshed with the live
+// state each instance manager reports over its control socket.
+func (app *App) GetRunningSessions() ([]types.RunningSession, error) {
+	session.CleanStale()
+
+	sessions := make([]types.RunningSession, 0)
+	for _, record := range session.LoadRecords() {
+		if response, err := session.Send(record.Pid, session.Request{Command: session.CommandStatus}); err == nil && response.Session != nil {
+			record = *response.Session
+		}
+		sessions = append(sessions, record)
+	}
+	return sessions, nil
+}
+
+// KillSession stops the session's game, escalating from a graceful close to
+// SIGKILL and wineserver -k, and reports which stage ended it. Sessions
+// without a control socket are stopped here from their registry record.
+func (app *App) KillSession(pid int) (*types.StopResult, error) {
+	response, err := session.Send(pid, session.Request{Command: session.CommandStop})
+	if errors.Is(err, session.ErrNoSocket) {
+		return stopRecordedSession(pid)
+	}
+	if response == nil {
+		return nil, err
+	}
+	return response.Stop, err
+}
+
+// stopRecordedSession runs the shutdown sequence on the game process group
+// named by the session's registry record.
+func stopRecordedSession(pid int) (*types.StopResult, error) {
+	record, found := session.LoadRecord(pid)
+	if !found || record.GamePid <= 0 {
+		return nil, fmt.Errorf("session %d has no control socket and no recorded game process", pid)
+	}
+	if !session.IsGameAlive(record) {
+		// The pid may belong to another process by now, so leave it alone.
+		return &types.StopResult{Stopped: true}, nil
+	}
+	process, err := os.FindProcess(record.GamePid)
+	if err != nil {
+		return nil, err
+	}
+
+	// The record holds what the session's backend resolved, which is empty
+	// for native games.
+	timeout := time.Duration(config.LoadAppSettings().StopTimeoutSeconds) * time.Second
+	stopOptions := executor.NewStopOptions(types.LaunchOptions{}, timeout)
+	stopOptions.WinePrefix = record.WinePrefix
+	stopOptions.Wineserver = record.Wineserver
+	result := executor.StopProcessGroup(process, stopOptions)
+	if !result.Stopped {
+		return &result, errors.New(result.Error)
+	}
+	return &result, nil
+}
+
+// ForceKillSession kills the session's whole process group immediately.
+func (app *App) ForceKillSession(pid int) error {
+	_, err := session.Send(pid, session.Request{Command: session.CommandKill})
+	return err
+}
+
+// ToggleSessionLogs opens or closes the session's log terminal and reports
+// whether it is now open.
+func (app *App) ToggleSessionLogs(pid int) (bool, error) {
+	response, err := session.Send(pid, session.Request{Command: session.CommandToggleLogs})
+	if err != nil {
+		return false, err
+	}
+	return response.ShowingLogs, nil
+}
+
+func (app *App) GetSessionLogTail(pid int, lines int) ([]string, error) {
+	response, err := session.Send(pid, session.Request{Command: session.CommandLogTail, Lines: lines})
+	if err != nil {
+		return nil, err
+	}
+	return response.Log, nil
+}
+
+// GetSessionMetrics returns the session's recent usage samples, oldest first.
+func (app *App) GetSessionMetrics(pid int) ([]types.SessionUsage, error) {
+	response, err := session.Send(pid, session.Request{Command: session.CommandMetrics})
+	if err != nil {
+		return nil, err
+	}
+	return response.Metrics, nil
+}
+
+func (app *App) GetSessionUsage(pid int) (*types.SessionUsage, error) {
+	response, err := session.Send(pid, session.Request{Command: session.CommandUsage})
+	if err != nil {
+		return nil, err
+	}
+	return response.Usage, nil
+}
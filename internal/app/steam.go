@@ -0,0 +1,143 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"light-launcher/internal/config"
+	"light-launcher/internal/system"
+	"light-launcher/internal/types"
+	"light-launcher/lib/vdf"
+)
+
+const steamShortcutTag = "LightLauncher"
+
+// ExportSteamShortcuts adds or updates a non-Steam shortcut for every saved
+// game in each Steam account's shortcuts.vdf, leaving other shortcuts alone.
+// Steam rewrites the file when it exits, so it should be closed first. The
+// count covers every account written; accounts that failed are reported in
+// the error.
+func (app *App) ExportSteamShortcuts() (int, error) {
+	instanceManagerPath := findInstanceManager()
+	if instanceManagerPath == "" {
+		return 0, fmt.Errorf("instance manager not found")
+	}
+
+	configs, err := config.ListGameConfigs()
+	if err != nil {
+		return 0, err
+	}
+
+	directories := system.GetSteamUserConfigDirectories()
+	if len(directories) == 0 {
+		return 0, fmt.Errorf("no Steam user data found")
+	}
+
+	// An account whose shortcuts.vdf cannot be read or written is skipped, so
+	// one broken file does not leave the others half exported.
+	exported := 0
+	var failures []error
+	for _, directory := range directories {
+		shortcutsPath := filepath.Join(directory, "shortcuts.vdf")
+		shortcuts, err := vdf.LoadShortcuts(shortcutsPath)
+		if err != nil {
+			failures = append(failures, fmt.Errorf("failed to read %s: %w", shortcutsPath, err))
+			continue
+		}
+
+		count := 0
+		for _, gameConfig := range configs {
+			if gameConfig.ID != "" {
+				upsertSteamShortcut(shortcuts, gameConfig, instanceManagerPath)
+				count++
+			}
+		}
+
+		if data, err := os.ReadFile(shortcutsPath); err == nil {
+			_ = os.WriteFile(shortcutsPath+".bak", data, 0644)
+		}
+		if err := shortcuts.Save(shortcutsPath); err != nil {
+			failures = append(failures, fmt.Errorf("failed to write %s: %w", shortcutsPath, err))
+			continue
+		}
+		exported += count
+	}
+	return exported, errors.Join(failures...)
+}
+
+func upsertSteamShortcut(shortcuts *vdf.Shortcuts, options types.LaunchOptions, instanceManagerPath string) {
+	launchOptions := "--game-id " + options.ID + " --logs=false"
+	name := gameDisplayName(options)
+	exe := `"` + instanceManagerPath + `"`
+	startDir := `"` + filepath.Dir(instanceManagerPath) + `"`
+
+	iconPath := config.GetGameIconPath(options.ID)
+	if _, err := os.Stat(iconPath); err != nil {
+		iconSource := options.LauncherPath
+		if iconSource == "" {
+			iconSource = options.GamePath
+		}
+		if err := saveIconAsPng(iconSource, iconPath); err != nil {
+			iconPath = ""
+		}
+	}
+
+	shortcut := shortcuts.Find(func(shortcut *vdf.Map) bool {
+		return isLightLauncherShortcut(shortcut) &&
+			strings.Contains(shortcut.GetString("LaunchOptions")+" ", "--game-id "+options.ID+" ")
+	})
+
+	if shortcut == nil {
+		tags := vdf.NewMap()
+		tags.Set("0", steamShortcutTag)
+
+		shortcut = vdf.NewMap()
+		// Derived from the game ID rather than the install path, so the
+		// shortcut and its artwork keep their app ID when LightLauncher moves.
+		shortcut.Set("appid", vdf.ShortcutAppID(steamShortcutTag+" "+options.ID, name))
+		shortcut.Set("AppName", name)
+		shortcut.Set("Exe", exe)
+		shortcut.Set("StartDir", startDir)
+		shortcut.Set("icon", iconPath)
+		shortcut.Set("ShortcutPath", "")
+		shortcut.Set("LaunchOptions", launchOptions)
+		shortcut.Set("IsHidden", uint32(0))
+		shortcut.Set("AllowDesktopConfig", uint32(1))
+		shortcut.Set("AllowOverlay", uint32(1))
+		shortcut.Set("OpenVR", uint32(0))
+		shortcut.Set("Devkit", uint32(0))
+		shortcut.Set("DevkitGameID", "")
+		shortcut.Set("DevkitOverrideAppID", uint32(0))
+		shortcut.Set("LastPlayTime", uint32(0))
+		shortcut.Set("FlatpakAppID", "")
+		shortcut.Set("tags", tags)
+		shortcuts.Add(shortcut)
+		return
+	}
+
+	// Keep appid and user-edited fields such as tags, so custom artwork survives.
+	shortcut.Set("AppName", name)
+	shortcut.Set("Exe", exe)
+	shortcut.Set("StartDir", startDir)
+	shortcut.Set("LaunchOptions", launchOptions)
+	if iconPath != "" {
+		shortcut.Set("icon", iconPath)
+	}
+}
+
+// isLightLauncherShortcut reports whether shortcut was exported by
+// LightLauncher. Shortcuts carry the LightLauncher tag; the executable name
+// covers those whose tag the user removed in Steam.
+func isLightLauncherShortcut(shortcut *vdf.Map) bool {
+	if tags := shortcut.GetMap("tags"); tags != nil {
+		for _, entry := range tags.Entries {
+			if tag, ok := entry.Value.(string); ok && tag == steamShortcutTag {
+				return true
+			}
+		}
+	}
+	return strings.Contains(filepath.Base(strings.Trim(shortcut.GetString("Exe"), `"`)), "light-launcher-instance")
+}
@@ -0,0 +1,44 @@
+package app
+
+import (
+	"light-launcher/internal/config"
+	"light-launcher/internal/types"
+	"light-launcher/lib/umu"
+)
+
+// FindUmuEntries suggests umu IDs for a game from the local umu-database,
+// matching the store ID within store first and the executable name second.
+func (app *App) FindUmuEntries(executablePath string, store string, storeID string) ([]types.UmuEntry, error) {
+	entries, err := umu.Load(config.GetUmuDatabasePath())
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]types.UmuEntry, 0)
+	for _, entry := range umu.Match(entries, executablePath, store, storeID) {
+		matches = append(matches, types.UmuEntry{
+			Title:    entry.Title,
+			Store:    entry.Store,
+			Codename: entry.Codename,
+			UmuID:    entry.UmuID,
+			Acronym:  entry.Acronym,
+			Notes:    entry.Notes,
+			ExeNames: entry.ExeNames,
+		})
+	}
+	return matches, nil
+}
+
+// RefreshUmuDatabase downloads the current umu-database and returns how many
+// entries it has.
+func (app *App) RefreshUmuDatabase() (int, error) {
+	entries, err := umu.Refresh(config.GetUmuDatabasePath())
+	if err != nil {
+		return 0, err
+	}
+	return len(entries), nil
+}
+
+func (app *App) GetUmuStores() []string {
+	return umu.Stores
+}
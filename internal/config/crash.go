@@ -0,0 +1,41 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"light-launcher/internal/types"
+)
+
+// SaveCrashReport replaces the game's last crash report.
+func SaveCrashReport(name string, id string, report types.CrashReport) error {
+	path := GetGameCrashReportPath(name, id)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// LoadCrashReport returns the game's last crash report, or nil if it has
+// never crashed.
+func LoadCrashReport(name string, id string) (*types.CrashReport, error) {
+	data, err := os.ReadFile(GetGameCrashReportPath(name, id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var report types.CrashReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"light-launcher/internal/types"
+)
+
+// RecentPlayWindow is how long after its last session a game counts as recent.
+const RecentPlayWindow = 14 * 24 * time.Hour
+
+// AppendPlaySession adds one finished session to the game's history. The
+// history is JSON lines so appending never rewrites earlier records.
+func AppendPlaySession(name string, id string, session types.PlaySession) error {
+	path := GetGameHistoryPath(name, id)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(data, '\n'))
+	return err
+}
+
+// LoadPlayHistory returns the game's sessions, oldest first. Lines that fail
+// to parse, such as one cut short by a crash, are skipped.
+func LoadPlayHistory(name string, id string) ([]types.PlaySession, error) {
+	file, err := os.Open(GetGameHistoryPath(name, id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []types.PlaySession{}, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	sessions := make([]types.PlaySession, 0)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var session types.PlaySession
+		if err := json.Unmarshal(scanner.Bytes(), &session); err == nil {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, scanner.Err()
+}
+
+func SummarizePlayHistory(sessions []types.PlaySession) types.PlaytimeSummary {
+	summary := types.PlaytimeSummary{SessionCount: len(sessions)}
+	for _, session := range sessions {
+		summary.TotalSeconds += session.DurationSeconds
+		if session.EndedAt > summary.LastPlayed {
+			summary.LastPlayed = session.EndedAt
+		}
+	}
+	return summary
+}
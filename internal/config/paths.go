@@ -21,6 +21,14 @@ func GetPrefixBaseDirectory() string {
 	return filepath.Join(GetBaseDirectory(), "prefixes")
 }
 
+func GetIconDirectory() string {
+	return filepath.Join(GetBaseDirectory(), "icons")
+}
+
+func GetGameIconPath(id string) string {
+	return filepath.Join(GetIconDirectory(), id+".png")
+}
+
 func GetExecutableConfigPath(name string, id string) string {
 	if id == "" {
 		return filepath.Join(GetConfigDirectory(), name)
@@ -33,8 +41,36 @@ func GetGameConfigFilePath(name string, id string) string {
 	return filepath.Join(GetExecutableConfigPath(name, id), "config.json")
 }
 
+func GetGameHistoryPath(name string, id string) string {
+	return filepath.Join(GetExecutableConfigPath(name, id), "history.jsonl")
+}
+
+func GetGameCrashReportPath(name string, id string) string {
+	return filepath.Join(GetExecutableConfigPath(name, id), "crash.json")
+}
+
+// GetCrashRulesPath holds user-defined crash signatures added to the defaults.
+func GetCrashRulesPath() string {
+	return filepath.Join(GetBaseDirectory(), "config", "crash-rules.json")
+}
+
+// GetUmuDatabasePath holds the local copy of the umu-database used to
+// suggest a game's umu ID.
+func GetUmuDatabasePath() string {
+	return filepath.Join(GetBaseDirectory(), "config", "umu-database.csv")
+}
+
+func GetLaunchScriptPath(name string, id string) string {
+	return filepath.Join(GetExecutableConfigPath(name, id), "launch.sh")
+}
+
+// GetGameLsfgConfigPath is the lsfg-vk config a launch points the layer at.
 func GetGameLsfgConfigPath(name string, id string) string {
-	return filepath.Join(GetExecutableConfigPath(name, id), "lsfg_vk.toml")
+	return filepath.Join(GetExecutableConfigPath(name, id), "lsfg-vk.toml")
+}
+
+func GetGameMangoHudConfigPath(name string, id string) string {
+	return filepath.Join(GetExecutableConfigPath(name, id), "MangoHud.conf")
 }
 
 func GetPrefixConfigPath(prefixName string) string {
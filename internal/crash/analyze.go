@@ -0,0 +1,99 @@
+package crash
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"light-launcher/internal/types"
+)
+
+// maxDetails caps how many distinct matches a finding lists.
+const maxDetails = 5
+
+// Analyze builds a crash report from how the game exited and the lines of
+// its session log. Only the first matching rule is applied to each line.
+func Analyze(exitCode int, signal string, logLines []string) types.CrashReport {
+	report := types.CrashReport{
+		CreatedAt: time.Now().Unix(),
+		ExitCode:  exitCode,
+		Signal:    signal,
+		Crashed:   exitCode != 0 || signal != "",
+		Findings:  []types.CrashFinding{},
+	}
+
+	rules := Rules()
+	findings := make(map[string]*types.CrashFinding)
+	var order []string
+
+	for _, line := range logLines {
+		for _, rule := range rules {
+			match := rule.expression.FindStringSubmatch(line)
+			if match == nil {
+				continue
+			}
+
+			finding, found := findings[rule.ID]
+			if !found {
+				finding = &types.CrashFinding{
+					Rule:    rule.ID,
+					Title:   rule.Title,
+					Hint:    rule.Hint,
+					Fatal:   rule.Fatal,
+					Details: []string{},
+					Line:    strings.TrimSpace(line),
+				}
+				findings[rule.ID] = finding
+				order = append(order, rule.ID)
+			}
+			if len(match) > 1 && match[1] != "" && len(finding.Details) < maxDetails && !contains(finding.Details, match[1]) {
+				finding.Details = append(finding.Details, match[1])
+			}
+			break
+		}
+	}
+
+	for _, id := range order {
+		finding := findings[id]
+		if finding.Fatal {
+			report.Crashed = true
+		}
+		report.Findings = append(report.Findings, *finding)
+	}
+	report.Summary = summarize(report)
+	return report
+}
+
+func summarize(report types.CrashReport) string {
+	for _, finding := range report.Findings {
+		if finding.Fatal {
+			return describe(finding)
+		}
+	}
+	if len(report.Findings) > 0 && report.Crashed {
+		return describe(report.Findings[0])
+	}
+	if report.Signal != "" {
+		return "Killed by " + report.Signal
+	}
+	if report.ExitCode != 0 {
+		return fmt.Sprintf("Exited with status %d", report.ExitCode)
+	}
+	return "Exited normally"
+}
+
+func describe(finding types.CrashFinding) string {
+	if len(finding.Details) > 0 {
+		return finding.Title + ": " + strings.Join(finding.Details, ", ")
+	}
+	return finding.Title
+}
+
+func contains(values []string, value string) bool {
+	for _, existing := range values {
+		if existing == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package crash
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sync"
+)
+
+// Rule matches one known failure signature in a session log. Fatal rules
+// mark the session as crashed even when the game exited with status 0,
+// which Wine often does after an unhandled exception.
+type Rule struct {
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Hint    string `json:"hint"`
+	Pattern string `json:"pattern"`
+	Fatal   bool   `json:"fatal"`
+
+	expression *regexp.Regexp
+}
+
+var DefaultRules = []Rule{
+	{
+		ID:      "missing-dll",
+		Title:   "Missing DLL",
+		Hint:    "Install the runtime that provides it (e.g. vcrun2022 or d3dcompiler_47 through protontricks) or try another Proton version.",
+		Pattern: `err:module:import_dll (?:Library|Loading library) (\S+)`,
+	},
+	{
+		ID:      "module-error",
+		Title:   "Module failed to load",
+		Hint:    "A DLL or one of its dependencies could not be initialised; check the lines around it in the log.",
+		Pattern: `err:module:\S+ (.*)`,
+	},
+	{
+		ID:      "device-lost",
+		Title:   "Vulkan device lost",
+		Hint:    "The GPU driver reset. Update the driver, lower settings, or disable overlays and frame generation.",
+		Pattern: `VK_ERROR_DEVICE_LOST|(?i:device lost)`,
+		Fatal:   true,
+	},
+	{
+		ID:      "vulkan-unavailable",
+		Title:   "Vulkan unavailable",
+		Hint:    "No usable Vulkan driver was found. Install the Vulkan driver for your GPU, including 32-bit libraries.",
+		Pattern: `VK_ERROR_INCOMPATIBLE_DRIVER|VK_ERROR_INITIALIZATION_FAILED|(?i:failed to create vulkan instance)`,
+		Fatal:   true,
+	},
+	{
+		ID:      "page-fault",
+		Title:   "Unhandled page fault",
+		Hint:    "The game crashed inside Wine. Try another Proton version or check the game's ProtonDB page.",
+		Pattern: `Unhandled page fault on (\S+ access to \S+)`,
+		Fatal:   true,
+	},
+	{
+		ID:      "unhandled-exception",
+		Title:   "Unhandled exception",
+		Hint:    "The game raised an exception it did not handle. Try another Proton version or verify the game files.",
+		Pattern: `Unhandled exception:? (\S+)`,
+		Fatal:   true,
+	},
+	{
+		ID:      "out-of-memory",
+		Title:   "Out of memory",
+		Hint:    "The game ran out of memory. Close other programs or enable Memory Protect.",
+		Pattern: `std::bad_alloc|E_OUTOFMEMORY|err:virtual:.*(?i:out of memory)`,
+	},
+	{
+		ID:      "umu-runtime-download",
+		Title:   "umu runtime download failed",
+		Hint:    "umu-run could not fetch the Steam Runtime or Proton. Check the network connection and free disk space, then launch again.",
+		Pattern: `^(?:umu\S*: )?(?:ERROR|CRITICAL):? .*(?i:download|fetch)`,
+	},
+}
+
+var (
+	rulesMutex    sync.Mutex
+	extraRules    []Rule
+	compiledRules []Rule
+	compileOnce   sync.Once
+)
+
+func (rule *Rule) compile() error {
+	expression, err := regexp.Compile(rule.Pattern)
+	if err != nil {
+		return fmt.Errorf("invalid pattern for rule %s: %w", rule.ID, err)
+	}
+	rule.expression = expression
+	return nil
+}
+
+// RegisterRule adds a rule that is checked after the default ones.
+func RegisterRule(rule Rule) error {
+	if err := rule.compile(); err != nil {
+		return err
+	}
+	rulesMutex.Lock()
+	defer rulesMutex.Unlock()
+	extraRules = append(extraRules, rule)
+	return nil
+}
+
+// LoadRules registers the rules listed in a JSON file. A missing file is not
+// an error, so users only create it when they want custom signatures.
+func LoadRules(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var rules []Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return fmt.Errorf("failed to parse crash rules: %w", err)
+	}
+	for _, rule := range rules {
+		if err := RegisterRule(rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rules returns the default rules followed by any registered ones. The
+// default rules are compiled on first use.
+func Rules() []Rule {
+	compileOnce.Do(func() {
+		for _, rule := range DefaultRules {
+			if rule.compile() == nil {
+				compiledRules = append(compiledRules, rule)
+			}
+		}
+	})
+
+	rulesMutex.Lock()
+	defer rulesMutex.Unlock()
+
+	rules := make([]Rule, 0, len(compiledRules)+len(extraRules))
+	rules = append(rules, compiledRules...)
+	return append(rules, extraRules...)
+}
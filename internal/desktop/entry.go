@@ -0,0 +1,126 @@
+package desktop
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const entryPrefix = "light-launcher-game-"
+
+// Entry describes the application menu entry of a saved game.
+type Entry struct {
+	ID       string
+	Name     string
+	Exec     []string
+	IconPath string
+}
+
+func GetApplicationsDirectory() string {
+	if dataHome := os.Getenv("XDG_DATA_HOME"); dataHome != "" {
+		return filepath.Join(dataHome, "applications")
+	}
+	homeDirectory, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".local", "share", "applications")
+	}
+	return filepath.Join(homeDirectory, ".local", "share", "applications")
+}
+
+func GetEntryPath(id string) string {
+	return filepath.Join(GetApplicationsDirectory(), entryPrefix+id+".desktop")
+}
+
+func WriteEntry(entry Entry) error {
+	if entry.ID == "" {
+		return fmt.Errorf("desktop entry requires a game ID")
+	}
+
+	icon := entry.IconPath
+	if icon == "" {
+		icon = "light-launcher"
+	}
+
+	var content strings.Builder
+	content.WriteString("[Desktop Entry]\n")
+	content.WriteString("Type=Application\n")
+	content.WriteString("Version=1.0\n")
+	content.WriteString("Name=" + escapeValue(entry.Name) + "\n")
+	content.WriteString("Comment=" + escapeValue("Launch "+entry.Name+" with LightLauncher") + "\n")
+	content.WriteString("Exec=" + escapeValue(formatExec(entry.Exec)) + "\n")
+	content.WriteString("Icon=" + escapeValue(icon) + "\n")
+	content.WriteString("Terminal=false\n")
+	content.WriteString("Categories=Game;\n")
+	content.WriteString("StartupNotify=false\n")
+	content.WriteString("X-LightLauncher-GameID=" + entry.ID + "\n")
+
+	path := GetEntryPath(entry.ID)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(content.String()), 0755); err != nil {
+		return err
+	}
+	refreshDatabase()
+	return nil
+}
+
+func RemoveEntry(id string) error {
+	if id == "" {
+		return nil
+	}
+	if err := os.Remove(GetEntryPath(id)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	refreshDatabase()
+	return nil
+}
+
+// ListEntryIDs returns the game IDs of all entries LightLauncher has written.
+func ListEntryIDs() ([]string, error) {
+	entries, err := os.ReadDir(GetApplicationsDirectory())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var ids []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && strings.HasPrefix(name, entryPrefix) && strings.HasSuffix(name, ".desktop") {
+			ids = append(ids, strings.TrimSuffix(strings.TrimPrefix(name, entryPrefix), ".desktop"))
+		}
+	}
+	return ids, nil
+}
+
+// formatExec quotes arguments following the Desktop Entry spec: arguments with
+// reserved characters are double-quoted and literal percent signs doubled.
+func formatExec(arguments []string) string {
+	quoted := make([]string, len(arguments))
+	for index, argument := range arguments {
+		argument = strings.ReplaceAll(argument, "%", "%%")
+		if argument == "" || strings.ContainsAny(argument, " \t\n\"'\\><~|&;$*?#()`") {
+			replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "`", "\\`", `$`, `\$`)
+			argument = `"` + replacer.Replace(argument) + `"`
+		}
+		quoted[index] = argument
+	}
+	return strings.Join(quoted, " ")
+}
+
+// escapeValue applies the string escapes of the Desktop Entry spec.
+func escapeValue(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "\n", `\n`, "\t", `\t`, "\r", `\r`)
+	return replacer.Replace(value)
+}
+
+func refreshDatabase() {
+	if _, err := exec.LookPath("update-desktop-database"); err == nil {
+		_ = exec.Command("update-desktop-database", GetApplicationsDirectory()).Run()
+	}
+}
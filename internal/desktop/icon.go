@@ -0,0 +1,166 @@
+package desktop
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+var pngSignature = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'}
+
+// ConvertIcoToPng picks the largest image in an .ico file and returns it as PNG.
+// Entries that are already PNG are returned as-is; BMP entries are decoded.
+func ConvertIcoToPng(data []byte) ([]byte, error) {
+	if bytes.HasPrefix(data, pngSignature) {
+		return data, nil
+	}
+	if len(data) < 6 || binary.LittleEndian.Uint16(data[0:2]) != 0 || binary.LittleEndian.Uint16(data[2:4]) != 1 {
+		return nil, fmt.Errorf("not an ico file")
+	}
+
+	count := int(binary.LittleEndian.Uint16(data[4:6]))
+	bestOffset, bestSize := 0, 0
+	bestArea, bestDepth := -1, -1
+	for index := 0; index < count; index++ {
+		entry := 6 + index*16
+		if entry+16 > len(data) {
+			break
+		}
+		width, height := int(data[entry]), int(data[entry+1])
+		if width == 0 {
+			width = 256
+		}
+		if height == 0 {
+			height = 256
+		}
+		depth := int(binary.LittleEndian.Uint16(data[entry+6 : entry+8]))
+		size := int(binary.LittleEndian.Uint32(data[entry+8 : entry+12]))
+		offset := int(binary.LittleEndian.Uint32(data[entry+12 : entry+16]))
+		if offset <= 0 || size <= 0 || offset+size > len(data) {
+			continue
+		}
+
+		area := width * height
+		if area > bestArea || (area == bestArea && depth > bestDepth) {
+			bestOffset, bestSize, bestArea, bestDepth = offset, size, area, depth
+		}
+	}
+	if bestArea < 0 {
+		return nil, fmt.Errorf("ico file has no usable images")
+	}
+
+	entryData := data[bestOffset : bestOffset+bestSize]
+	if bytes.HasPrefix(entryData, pngSignature) {
+		return entryData, nil
+	}
+
+	decoded, err := decodeIconBitmap(entryData)
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, decoded); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// decodeIconBitmap decodes the headerless DIB stored in an .ico entry: a
+// BITMAPINFOHEADER, an optional palette, the colour rows and the AND mask.
+func decodeIconBitmap(data []byte) (image.Image, error) {
+	if len(data) < 40 {
+		return nil, fmt.Errorf("icon bitmap too short")
+	}
+	headerSize := int(binary.LittleEndian.Uint32(data[0:4]))
+	width := int(int32(binary.LittleEndian.Uint32(data[4:8])))
+	height := int(int32(binary.LittleEndian.Uint32(data[8:12]))) / 2
+	depth := int(binary.LittleEndian.Uint16(data[14:16]))
+	compression := binary.LittleEndian.Uint32(data[16:20])
+	colorsUsed := int(binary.LittleEndian.Uint32(data[32:36]))
+
+	if width <= 0 || height <= 0 || width > 1024 || height > 1024 {
+		return nil, fmt.Errorf("unsupported icon size %dx%d", width, height)
+	}
+	if headerSize < 40 || headerSize > len(data) {
+		return nil, fmt.Errorf("invalid icon bitmap header")
+	}
+	if compression != 0 {
+		return nil, fmt.Errorf("unsupported icon compression %d", compression)
+	}
+
+	offset := headerSize
+	var palette []color.NRGBA
+	if depth <= 8 {
+		if colorsUsed == 0 || colorsUsed > 1<<depth {
+			colorsUsed = 1 << depth
+		}
+		if offset+colorsUsed*4 > len(data) {
+			return nil, fmt.Errorf("icon palette truncated")
+		}
+		for index := 0; index < colorsUsed; index++ {
+			entry := data[offset+index*4:]
+			palette = append(palette, color.NRGBA{R: entry[2], G: entry[1], B: entry[0], A: 255})
+		}
+		offset += colorsUsed * 4
+	}
+
+	switch depth {
+	case 1, 4, 8, 24, 32:
+	default:
+		return nil, fmt.Errorf("unsupported icon depth %d", depth)
+	}
+
+	stride := ((width*depth + 31) / 32) * 4
+	maskStride := ((width + 31) / 32) * 4
+	maskOffset := offset + stride*height
+	hasMask := maskOffset+maskStride*height <= len(data)
+	if offset+stride*height > len(data) {
+		return nil, fmt.Errorf("icon pixels truncated")
+	}
+
+	result := image.NewNRGBA(image.Rect(0, 0, width, height))
+	hasAlpha := false
+	for y := 0; y < height; y++ {
+		row := data[offset+(height-1-y)*stride:]
+		for x := 0; x < width; x++ {
+			var pixel color.NRGBA
+			switch depth {
+			case 32:
+				pixel = color.NRGBA{R: row[x*4+2], G: row[x*4+1], B: row[x*4], A: row[x*4+3]}
+				if pixel.A != 0 {
+					hasAlpha = true
+				}
+			case 24:
+				pixel = color.NRGBA{R: row[x*3+2], G: row[x*3+1], B: row[x*3], A: 255}
+			default:
+				bit := x * depth
+				index := int(row[bit/8]>>(8-depth-bit%8)) & (1<<depth - 1)
+				if index < len(palette) {
+					pixel = palette[index]
+				}
+			}
+			result.SetNRGBA(x, y, pixel)
+		}
+	}
+
+	if hasMask && (depth != 32 || !hasAlpha) {
+		for y := 0; y < height; y++ {
+			row := data[maskOffset+(height-1-y)*maskStride:]
+			for x := 0; x < width; x++ {
+				pixel := result.NRGBAAt(x, y)
+				if row[x/8]&(0x80>>(x%8)) != 0 {
+					pixel.A = 0
+				} else {
+					pixel.A = 255
+				}
+				result.SetNRGBA(x, y, pixel)
+			}
+		}
+	}
+
+	return result, nil
+}
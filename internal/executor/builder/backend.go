@@ -0,0 +1,332 @@
+package builder
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"light-launcher/internal/config"
+	"light-launcher/internal/executor"
+	"light-launcher/internal/system"
+	"light-launcher/internal/types"
+	"light-launcher/lib/umu"
+)
+
+// Launch backends, stored in LaunchOptions.Backend. An empty Backend runs
+// Linux executables natively and everything else through umu-run.
+const (
+	BackendUmu    = "umu"
+	BackendProton = "proton"
+	BackendWine   = "wine"
+	BackendNative = "native"
+)
+
+// LaunchBackend starts the game executable. BuildCommand adds its environment
+// and puts its command inside the wrapper chain.
+type LaunchBackend interface {
+	Name() string
+	// Runner is the program that starts the game, shown in the wrapper chain.
+	Runner(options types.LaunchOptions) string
+	Environment(options types.LaunchOptions) [][2]string
+	Command(options types.LaunchOptions, executablePath string) []string
+	// Validate reports why the backend cannot launch the game on this system.
+	Validate(options types.LaunchOptions) error
+	// Prepare readies the prefix right before the game starts.
+	Prepare(options types.LaunchOptions) error
+	// Wineserver is the wineserver matching the Wine build the backend runs,
+	// or "" when the game does not run under Wine.
+	Wineserver(options types.LaunchOptions) string
+}
+
+var Backends = []LaunchBackend{umuBackend{}, protonBackend{}, wineBackend{}, nativeBackend{}}
+
+// GetBackend returns the backend options selects. An empty name picks the
+// native backend for Linux executables and umu-run otherwise; unknown names
+// fall back to umu-run and are rejected by ValidateBackend.
+func GetBackend(options types.LaunchOptions) LaunchBackend {
+	if options.Backend == "" && system.IsNativeExecutable(launchExecutable(options)) {
+		return nativeBackend{}
+	}
+	if backend, found := findBackend(options.Backend); found {
+		return backend
+	}
+	return umuBackend{}
+}
+
+func BackendNames() []string {
+	names := make([]string, 0, len(Backends))
+	for _, backend := range Backends {
+		names = append(names, backend.Name())
+	}
+	return names
+}
+
+func ValidateBackend(options types.LaunchOptions) error {
+	if _, found := findBackend(options.Backend); !found {
+		return fmt.Errorf("unknown launch backend %q", options.Backend)
+	}
+	return GetBackend(options).Validate(options)
+}
+
+func findBackend(name string) (LaunchBackend, bool) {
+	if name == "" {
+		name = BackendUmu
+	}
+	for _, backend := range Backends {
+		if backend.Name() == name {
+			return backend, true
+		}
+	}
+	return nil, false
+}
+
+func (builder *CommandBuilder) addLauncher() {
+	backend := GetBackend(builder.Options)
+	builder.Arguments = append(builder.Arguments, backend.Command(builder.Options, launchExecutable(builder.Options))...)
+}
+
+func launchExecutable(options types.LaunchOptions) string {
+	if options.LauncherPath != "" {
+		return config.ExpandPath(options.LauncherPath)
+	}
+	return config.ExpandPath(options.GamePath)
+}
+
+func prefixEnvironment(options types.LaunchOptions) [2]string {
+	return [2]string{"WINEPREFIX", config.ExpandPath(options.PrefixPath)}
+}
+
+// WinePrefix returns the WINEPREFIX the selected backend sets, or "" when the
+// game does not run under Wine.
+func WinePrefix(options types.LaunchOptions) string {
+	for _, variable := range GetBackend(options).Environment(options) {
+		if variable[0] == "WINEPREFIX" {
+			return variable[1]
+		}
+	}
+	return ""
+}
+
+// umuBackend runs the game through umu-run, which downloads the Steam Runtime
+// and applies protonfixes.
+type umuBackend struct{}
+
+func (umuBackend) Name() string { return BackendUmu }
+
+func (umuBackend) Runner(types.LaunchOptions) string { return "umu-run" }
+
+func (umuBackend) Environment(options types.LaunchOptions) [][2]string {
+	variables := [][2]string{prefixEnvironment(options)}
+	if options.ProtonPath != "" {
+		variables = append(variables,
+			[2]string{"UMU_PROTON_PATTERN", filepath.Base(options.ProtonPath)},
+			[2]string{"PROTONPATH", config.ExpandPath(options.ProtonPath)},
+		)
+	}
+	return append(variables, UmuEnvironment(options)...)
+}
+
+func (umuBackend) Command(_ types.LaunchOptions, executablePath string) []string {
+	return []string{"umu-run", executablePath}
+}
+
+func (umuBackend) Validate(types.LaunchOptions) error {
+	if !system.IsCommandAvailable("umu-run") {
+		return fmt.Errorf("umu-run is not installed, install umu-launcher or pick another launch backend")
+	}
+	return nil
+}
+
+func (umuBackend) Prepare(types.LaunchOptions) error { return nil }
+
+func (umuBackend) Wineserver(options types.LaunchOptions) string {
+	return executor.FindWineserver(config.ExpandPath(options.ProtonPath))
+}
+
+// UmuEnvironment identifies the game to umu-run so it applies the game's
+// protonfixes instead of running it as umu-default.
+func UmuEnvironment(options types.LaunchOptions) [][2]string {
+	var variables [][2]string
+	if gameID := umu.GameID(options.UmuGameID); gameID != "" {
+		variables = append(variables, [2]string{"GAMEID", gameID})
+	}
+	if store := umu.Store(options.UmuStore); store != "" {
+		variables = append(variables, [2]string{"STORE", store})
+	}
+	return variables
+}
+
+// protonBackend runs the proton script of the selected build directly, the
+// way Steam does, without the Steam Runtime container.
+type protonBackend struct{}
+
+func (protonBackend) Name() string { return BackendProton }
+
+func (protonBackend) Runner(types.LaunchOptions) string { return "proton" }
+
+func (protonBackend) Environment(options types.LaunchOptions) [][2]string {
+	variables := [][2]string{
+		prefixEnvironment(options),
+		{"STEAM_COMPAT_DATA_PATH", compatDataPath(options)},
+		{"STEAM_COMPAT_CLIENT_INSTALL_PATH", steamClientPath()},
+	}
+	// GE-Proton's protonfixes read the umu ID without umu-run.
+	if gameID := umu.GameID(options.UmuGameID); gameID != "" {
+		variables = append(variables, [2]string{"UMU_ID", gameID})
+	}
+	if store := umu.Store(options.UmuStore); store != "" {
+		variables = append(variables, [2]string{"STORE", store})
+	}
+	return variables
+}
+
+func (protonBackend) Command(options types.LaunchOptions, executablePath string) []string {
+	return []string{protonScript(options), "run", executablePath}
+}
+
+func (protonBackend) Validate(options types.LaunchOptions) error {
+	if options.ProtonPath == "" {
+		return fmt.Errorf("the proton backend needs a Proton build directory")
+	}
+	if _, err := os.Stat(protonScript(options)); err != nil {
+		return fmt.Errorf("no proton script in %s", options.ProtonPath)
+	}
+	return nil
+}
+
+// Prepare links pfx to the prefix itself, since Proton keeps the Wine prefix
+// in STEAM_COMPAT_DATA_PATH/pfx. umu-run sets prefixes up the same way.
+func (protonBackend) Prepare(options types.LaunchOptions) error {
+	prefix := config.ExpandPath(options.PrefixPath)
+	if filepath.Base(prefix) == "pfx" {
+		return nil
+	}
+	if err := os.MkdirAll(prefix, 0755); err != nil {
+		return err
+	}
+	link := filepath.Join(prefix, "pfx")
+	if _, err := os.Lstat(link); err == nil {
+		return nil
+	}
+	return os.Symlink(".", link)
+}
+
+func (protonBackend) Wineserver(options types.LaunchOptions) string {
+	return executor.FindWineserver(config.ExpandPath(options.ProtonPath))
+}
+
+func protonScript(options types.LaunchOptions) string {
+	return filepath.Join(config.ExpandPath(options.ProtonPath), "proton")
+}
+
+// compatDataPath is the directory Proton keeps its prefix in. A prefix taken
+// from Steam's compatdata already is the pfx directory inside it.
+func compatDataPath(options types.LaunchOptions) string {
+	prefix := config.ExpandPath(options.PrefixPath)
+	if filepath.Base(prefix) == "pfx" {
+		return filepath.Dir(prefix)
+	}
+	return prefix
+}
+
+// steamClientPath points Proton at a Steam install for its Steam client
+// libraries. Proton only needs the variable to be set when Steam is missing.
+func steamClientPath() string {
+	if roots := system.GetSteamRoots(); len(roots) > 0 {
+		return roots[0]
+	}
+	return ""
+}
+
+// wineBackend runs a system or custom wine binary. Proton's own switches and
+// fixes do not apply.
+type wineBackend struct{}
+
+func (wineBackend) Name() string { return BackendWine }
+
+func (wineBackend) Runner(options types.LaunchOptions) string {
+	if options.WinePath != "" {
+		return config.ExpandPath(options.WinePath)
+	}
+	return "wine"
+}
+
+func (wineBackend) Environment(options types.LaunchOptions) [][2]string {
+	return [][2]string{prefixEnvironment(options)}
+}
+
+func (backend wineBackend) Command(options types.LaunchOptions, executablePath string) []string {
+	return []string{backend.Runner(options), executablePath}
+}
+
+func (backend wineBackend) Validate(options types.LaunchOptions) error {
+	runner := backend.Runner(options)
+	if strings.Contains(runner, "/") {
+		if info, err := os.Stat(runner); err != nil || info.IsDir() {
+			return fmt.Errorf("wine binary not found at %s", runner)
+		}
+		return nil
+	}
+	if !system.IsCommandAvailable(runner) {
+		return fmt.Errorf("wine is not installed, install it or set the path of a wine binary")
+	}
+	return nil
+}
+
+func (wineBackend) Prepare(types.LaunchOptions) error { return nil }
+
+func (backend wineBackend) Wineserver(options types.LaunchOptions) string {
+	runner := backend.Runner(options)
+	if !strings.Contains(runner, "/") {
+		if path, err := exec.LookPath(runner); err == nil {
+			runner = path
+		}
+	}
+	candidate := filepath.Join(filepath.Dir(runner), "wineserver")
+	if _, err := os.Stat(candidate); err == nil {
+		return candidate
+	}
+	return ""
+}
+
+// nativeBackend runs Linux binaries, AppImages and shell scripts directly,
+// so only the wrappers around the game apply.
+type nativeBackend struct{}
+
+func (nativeBackend) Name() string { return BackendNative }
+
+func (backend nativeBackend) Runner(options types.LaunchOptions) string {
+	return backend.Command(options, launchExecutable(options))[0]
+}
+
+func (nativeBackend) Environment(types.LaunchOptions) [][2]string { return nil }
+
+// Command runs scripts that lost their execute bit, as happens after copying
+// from some file systems, through the interpreter on their #! line, or
+// through sh for .sh files without one.
+func (nativeBackend) Command(_ types.LaunchOptions, executablePath string) []string {
+	if system.GetExecutableKind(executablePath) != system.ExecutableScript || system.IsExecutableFile(executablePath) {
+		return []string{executablePath}
+	}
+	if interpreter := system.ReadShebang(executablePath); interpreter != nil {
+		return append(interpreter, executablePath)
+	}
+	return []string{"sh", executablePath}
+}
+
+func (nativeBackend) Validate(options types.LaunchOptions) error {
+	executablePath := launchExecutable(options)
+	if !system.IsNativeExecutable(executablePath) {
+		return fmt.Errorf("%s is not a Linux executable, AppImage or shell script", filepath.Base(executablePath))
+	}
+	if system.GetExecutableKind(executablePath) != system.ExecutableScript && !system.IsExecutableFile(executablePath) {
+		return fmt.Errorf("%s is not executable, run chmod +x on it", filepath.Base(executablePath))
+	}
+	return nil
+}
+
+func (nativeBackend) Prepare(types.LaunchOptions) error { return nil }
+
+func (nativeBackend) Wineserver(types.LaunchOptions) string { return "" }
@@ -1,9 +1,9 @@
 package builder
 
 import (
-	"fmt"
-	"light-launcher/internal/config"
+	"light-launcher/internal/system"
 	"light-launcher/internal/types"
+	"light-launcher/lib/lsfg"
 	"os"
 	"path/filepath"
 	"strings"
@@ -23,9 +23,18 @@ func NewCommandBuilder(options types.LaunchOptions) *CommandBuilder {
 }
 
 func BuildCommand(options types.LaunchOptions) ([]string, []string) {
-	builder := NewCommandBuilder(options)
+	return buildCommand(NewCommandBuilder(options))
+}
+
+// buildCommand applies every launch setting on top of builder's starting
+// environment, so a builder started empty yields only the launch variables.
+func buildCommand(builder *CommandBuilder) ([]string, []string) {
+	builder.Options = ResolveGpuDevice(builder.Options)
+	options := builder.Options
 
 	builder.buildBaseEnvironment()
+	builder.applyGpuSelection()
+	builder.applyProtonOptions()
 	builder.applyLsfg()
 
 	if !options.Extras.Gamescope.Enabled {
@@ -39,56 +48,135 @@ func BuildCommand(options types.LaunchOptions) ([]string, []string) {
 		builder.applyMangoHud()
 	}
 
-	builder.addUmuRun()
+	builder.addLauncher()
 	builder.addCustomArgs()
-	builder.applyMemoryProtection()
+	builder.applyScheduling()
+	builder.applyScope()
+	builder.applyCustomEnvironment()
 
 	return builder.Arguments, builder.Environment
 }
 
-func (builder *CommandBuilder) buildBaseEnvironment() {
-	builder.Environment = append(builder.Environment,
-		fmt.Sprintf("WINEPREFIX=%s", config.ExpandPath(builder.Options.PrefixPath)),
-	)
+// WrapperChain lists the programs BuildCommand wraps the game in, outermost
+// first, ending with the backend's runner that starts the game itself.
+func WrapperChain(options types.LaunchOptions) []string {
+	var wrappers []string
+	if usesScope(options) {
+		wrappers = append(wrappers, "systemd-run")
+	}
+	wrappers = append(wrappers, schedulingWrapperNames(options.Extras.Scheduling)...)
 
-	if builder.Options.ProtonPath != "" {
-		protonPattern := filepath.Base(builder.Options.ProtonPath)
-		builder.Environment = append(builder.Environment,
-			fmt.Sprintf("UMU_PROTON_PATTERN=%s", protonPattern),
-			fmt.Sprintf("PROTONPATH=%s", config.ExpandPath(builder.Options.ProtonPath)),
-		)
+	prefix, _, _ := splitCustomArgs(options.CustomArgs)
+	for len(prefix) > 0 && isEnvironmentAssignment(prefix[0]) {
+		prefix = prefix[1:]
+	}
+	if len(prefix) > 0 {
+		wrappers = append(wrappers, filepath.Base(prefix[0]))
 	}
-}
 
-func (builder *CommandBuilder) addUmuRun() {
-	builder.Arguments = append(builder.Arguments, "umu-run")
+	if options.Extras.EnableGamemode && system.IsCommandAvailable("gamemoderun") {
+		wrappers = append(wrappers, "gamemoderun")
+	}
+	if options.Extras.Gamescope.Enabled && system.IsCommandAvailable("gamescope") {
+		wrappers = append(wrappers, "gamescope")
+	}
+	return append(wrappers, filepath.Base(GetBackend(options).Runner(options)))
+}
 
-	executablePath := builder.Options.LauncherPath
-	if executablePath == "" {
-		executablePath = builder.Options.GamePath
+func (builder *CommandBuilder) buildBaseEnvironment() {
+	for _, variable := range GetBackend(builder.Options).Environment(builder.Options) {
+		builder.Environment = append(builder.Environment, variable[0]+"="+variable[1])
 	}
-	builder.Arguments = append(builder.Arguments, executablePath)
 }
 
 func (builder *CommandBuilder) addCustomArgs() {
-	if builder.Options.CustomArgs != "" {
-		arguments := strings.Fields(builder.Options.CustomArgs)
-		builder.Arguments = append(builder.Arguments, arguments...)
+	if builder.Options.CustomArgs == "" {
+		return
 	}
+
+	// Invalid arguments are rejected by ValidateCustomArgs before launch.
+	prefix, suffix, _ := splitCustomArgs(builder.Options.CustomArgs)
+	for len(prefix) > 0 && isEnvironmentAssignment(prefix[0]) {
+		key, _, _ := strings.Cut(prefix[0], "=")
+		builder.Environment = append(removeEnvironmentKey(builder.Environment, key), prefix[0])
+		prefix = prefix[1:]
+	}
+
+	arguments := append([]string{}, prefix...)
+	arguments = append(arguments, builder.Arguments...)
+	builder.Arguments = append(arguments, suffix...)
 }
 
+// FormatCommandForDisplay renders the command with the variables LightLauncher
+// sets as a single line that can be pasted into a POSIX shell.
 func FormatCommandForDisplay(commandArguments []string, options types.LaunchOptions) string {
-	var builder strings.Builder
-	if options.Extras.Memory.Enabled && options.Extras.Memory.Value != "" {
-		builder.WriteString(fmt.Sprintf("[MemMin:%s] ", options.Extras.Memory.Value))
+	var assignments [][2]string
+	var unset []string
+
+	removeVariable := func(key string) {
+		for index := range assignments {
+			if assignments[index][0] == key {
+				assignments = append(assignments[:index], assignments[index+1:]...)
+				return
+			}
+		}
 	}
-	builder.WriteString("WINEPREFIX=" + options.PrefixPath + " ")
-	if options.ProtonPath != "" {
-		builder.WriteString("UMU_PROTON_PATTERN=" + filepath.Base(options.ProtonPath) + " ")
+	setVariable := func(key, value string) {
+		removeVariable(key)
+		assignments = append(assignments, [2]string{key, value})
+	}
+
+	for _, variable := range GetBackend(options).Environment(options) {
+		setVariable(variable[0], variable[1])
+	}
+	for _, variable := range GpuEnvironment(options) {
+		setVariable(variable[0], variable[1])
+	}
+	protonVariables, _ := ProtonEnvironment(options)
+	for _, variable := range protonVariables {
+		setVariable(variable[0], variable[1])
+	}
+	if options.Extras.Lsfg.Enabled {
+		setVariable(lsfg.ConfigEnvironment, GetLsfgConfigPath(options))
 	}
 	if options.Extras.EnableMangoHud {
-		builder.WriteString("MANGOHUD=1 ")
+		setVariable("MANGOHUD", "1")
+		if path := mangoHudConfigPath(options.Name, options.ID, options.Extras.MangoHud.PerGame); path != "" {
+			setVariable("MANGOHUD_CONFIGFILE", path)
+		}
+	}
+
+	prefix, _, _ := splitCustomArgs(options.CustomArgs)
+	for _, word := range prefix {
+		if !isEnvironmentAssignment(word) {
+			break
+		}
+		key, value, _ := strings.Cut(word, "=")
+		setVariable(key, value)
+	}
+
+	for _, variable := range options.Environment {
+		if variable.Key == "" {
+			continue
+		}
+		if variable.Unset {
+			removeVariable(variable.Key)
+			unset = append(unset, variable.Key)
+		} else {
+			setVariable(variable.Key, variable.Value)
+		}
+	}
+
+	var parts []string
+	if len(unset) > 0 {
+		parts = append(parts, "env")
+		for _, key := range unset {
+			parts = append(parts, "-u", QuoteShellWord(key))
+		}
+	}
+	for _, assignment := range assignments {
+		parts = append(parts, assignment[0]+"="+QuoteShellWord(assignment[1]))
 	}
-	builder.WriteString(strings.Join(commandArguments, " "))
-	return builder.String()
+	parts = append(parts, JoinShellWords(commandArguments))
+	return strings.Join(parts, " ")
 }
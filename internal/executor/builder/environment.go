@@ -0,0 +1,44 @@
+package builder
+
+import (
+	"strings"
+
+	"light-launcher/internal/types"
+)
+
+// applyCustomEnvironment applies the per-game variables in order, so later
+// entries win over earlier ones and over anything the builder set itself.
+func (builder *CommandBuilder) applyCustomEnvironment() {
+	for _, variable := range builder.Options.Environment {
+		key := strings.TrimSpace(variable.Key)
+		if key == "" || strings.Contains(key, "=") {
+			continue
+		}
+
+		builder.Environment = removeEnvironmentKey(builder.Environment, key)
+		if !variable.Unset {
+			builder.Environment = append(builder.Environment, key+"="+variable.Value)
+		}
+	}
+}
+
+func removeEnvironmentKey(environment []string, key string) []string {
+	prefix := key + "="
+	filtered := environment[:0]
+	for _, entry := range environment {
+		if !strings.HasPrefix(entry, prefix) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+// ParseEnvironmentVariable parses a KEY=VALUE pair as passed on the command line.
+func ParseEnvironmentVariable(pair string) (types.EnvironmentVariable, bool) {
+	key, value, found := strings.Cut(pair, "=")
+	key = strings.TrimSpace(key)
+	if !found || key == "" {
+		return types.EnvironmentVariable{}, false
+	}
+	return types.EnvironmentVariable{Key: key, Value: value}, true
+}
@@ -1,19 +1,107 @@
 package builder
 
-import "light-launcher/internal/system"
+import (
+	"fmt"
+	"strconv"
+
+	"light-launcher/internal/system"
+	"light-launcher/internal/types"
+)
+
+var (
+	GamescopeFilters = []string{"linear", "nearest", "fsr", "nis", "pixel"}
+	GamescopeScalers = []string{"auto", "integer", "fit", "fill", "stretch"}
+)
 
 func (builder *CommandBuilder) applyGamescope() {
-	if builder.Options.Extras.Gamescope.Enabled && system.IsCommandAvailable("gamescope") {
+	gamescope := builder.Options.Extras.Gamescope
+	if gamescope.Enabled && system.IsCommandAvailable("gamescope") {
 		builder.Arguments = append(builder.Arguments, "gamescope")
-		if builder.Options.Extras.Gamescope.Width != "" {
-			builder.Arguments = append(builder.Arguments, "-w", builder.Options.Extras.Gamescope.Width)
+		builder.Arguments = append(builder.Arguments, gamescopeArguments(gamescope)...)
+		builder.Arguments = append(builder.Arguments, "--", "env")
+	}
+}
+
+func gamescopeArguments(gamescope types.GamescopeConfig) []string {
+	var arguments []string
+	addValue := func(flag, value string) {
+		if value != "" {
+			arguments = append(arguments, flag, value)
+		}
+	}
+	addSwitch := func(flag string, enabled bool) {
+		if enabled {
+			arguments = append(arguments, flag)
+		}
+	}
+
+	addValue("-w", gamescope.Width)
+	addValue("-h", gamescope.Height)
+	addValue("-r", gamescope.RefreshRate)
+	addValue("-W", gamescope.OutputWidth)
+	addValue("-H", gamescope.OutputHeight)
+	addSwitch("-f", gamescope.Fullscreen)
+	addSwitch("-b", gamescope.Borderless)
+	addValue("-F", gamescope.Filter)
+	addValue("-S", gamescope.Scaler)
+	addValue("--sharpness", gamescope.Sharpness)
+	addValue("--framerate-limit", gamescope.FrameLimit)
+	addSwitch("--hdr-enabled", gamescope.HDR)
+	addSwitch("--force-grab-cursor", gamescope.ForceGrabCursor)
+	addSwitch("--adaptive-sync", gamescope.AdaptiveSync)
+	addSwitch("--mangoapp", gamescope.MangoApp)
+	return arguments
+}
+
+// ValidateGamescope rejects option combinations gamescope refuses or
+// silently ignores, so the user sees the problem before the game starts.
+func ValidateGamescope(extras types.ExtrasConfig) error {
+	gamescope := extras.Gamescope
+	if !gamescope.Enabled {
+		return nil
+	}
+
+	numbers := []struct {
+		name  string
+		value string
+	}{
+		{"width", gamescope.Width},
+		{"height", gamescope.Height},
+		{"refresh rate", gamescope.RefreshRate},
+		{"output width", gamescope.OutputWidth},
+		{"output height", gamescope.OutputHeight},
+		{"frame limit", gamescope.FrameLimit},
+	}
+	for _, number := range numbers {
+		if number.value == "" {
+			continue
 		}
-		if builder.Options.Extras.Gamescope.Height != "" {
-			builder.Arguments = append(builder.Arguments, "-h", builder.Options.Extras.Gamescope.Height)
+		if value, err := strconv.Atoi(number.value); err != nil || value <= 0 {
+			return fmt.Errorf("gamescope %s must be a positive whole number, got %q", number.name, number.value)
 		}
-		if builder.Options.Extras.Gamescope.RefreshRate != "" {
-			builder.Arguments = append(builder.Arguments, "-r", builder.Options.Extras.Gamescope.RefreshRate)
+	}
+
+	if gamescope.Fullscreen && gamescope.Borderless {
+		return fmt.Errorf("gamescope cannot be fullscreen and borderless at the same time")
+	}
+	if gamescope.Filter != "" && !contains(GamescopeFilters, gamescope.Filter) {
+		return fmt.Errorf("unknown gamescope filter %q", gamescope.Filter)
+	}
+	if gamescope.Scaler != "" && !contains(GamescopeScalers, gamescope.Scaler) {
+		return fmt.Errorf("unknown gamescope scaler %q", gamescope.Scaler)
+	}
+
+	if gamescope.Sharpness != "" {
+		if gamescope.Filter != "fsr" && gamescope.Filter != "nis" {
+			return fmt.Errorf("gamescope sharpness only applies to the fsr and nis filters")
 		}
-		builder.Arguments = append(builder.Arguments, "--", "env")
+		if value, err := strconv.Atoi(gamescope.Sharpness); err != nil || value < 0 || value > 20 {
+			return fmt.Errorf("gamescope sharpness must be between 0 and 20, got %q", gamescope.Sharpness)
+		}
+	}
+
+	if gamescope.MangoApp && extras.EnableMangoHud {
+		return fmt.Errorf("use either MangoHud or gamescope's --mangoapp, not both")
 	}
+	return nil
 }
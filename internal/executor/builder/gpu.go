@@ -0,0 +1,65 @@
+package builder
+
+import (
+	"strings"
+
+	"light-launcher/internal/system"
+	"light-launcher/internal/types"
+)
+
+// ResolveGpuDevice looks up the selected GPU and keeps it in the options.
+// Finding it scans sysfs and runs vulkaninfo, so a launch resolves it once
+// before building, displaying and logging the command.
+func ResolveGpuDevice(options types.LaunchOptions) types.LaunchOptions {
+	if options.Extras.GpuDevice != "" && options.ResolvedGpu == nil {
+		device, _ := system.FindGpuDevice(options.Extras.GpuDevice)
+		options.ResolvedGpu = &device
+	}
+	return options
+}
+
+// SelectedGpu returns the selected GPU, if it is still present.
+func SelectedGpu(options types.LaunchOptions) (types.GpuDevice, bool) {
+	if options.Extras.GpuDevice == "" {
+		return types.GpuDevice{}, false
+	}
+	if options.ResolvedGpu != nil {
+		return *options.ResolvedGpu, options.ResolvedGpu.PciAddress != ""
+	}
+	return system.FindGpuDevice(options.Extras.GpuDevice)
+}
+
+// GpuEnvironment returns the variables that make the game render on the
+// selected GPU. The NVIDIA proprietary driver ignores DRI_PRIME and is
+// selected through its PRIME render offload variables instead. Nothing is
+// set when no GPU is selected or it is no longer present.
+func GpuEnvironment(options types.LaunchOptions) [][2]string {
+	device, found := SelectedGpu(options)
+	if !found {
+		return nil
+	}
+
+	var variables [][2]string
+	if device.Driver == "nvidia" {
+		variables = append(variables,
+			[2]string{"__NV_PRIME_RENDER_OFFLOAD", "1"},
+			[2]string{"__GLX_VENDOR_LIBRARY_NAME", "nvidia"},
+			[2]string{"__VK_LAYER_NV_optimus", "NVIDIA_only"},
+		)
+	} else {
+		variables = append(variables, [2]string{"DRI_PRIME", "pci-" + strings.NewReplacer(":", "_", ".", "_").Replace(device.PciAddress)})
+	}
+	if device.VendorID != "" && device.DeviceID != "" {
+		variables = append(variables, [2]string{"MESA_VK_DEVICE_SELECT", device.VendorID + ":" + device.DeviceID})
+	}
+	if device.Name != "" && !strings.Contains(device.Name, device.VendorID+":"+device.DeviceID) {
+		variables = append(variables, [2]string{"DXVK_FILTER_DEVICE_NAME", device.Name})
+	}
+	return variables
+}
+
+func (builder *CommandBuilder) applyGpuSelection() {
+	for _, variable := range GpuEnvironment(builder.Options) {
+		builder.Environment = append(removeEnvironmentKey(builder.Environment, variable[0]), variable[0]+"="+variable[1])
+	}
+}
@@ -1,9 +1,82 @@
 package builder
 
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"light-launcher/internal/config"
+	"light-launcher/internal/system"
+	"light-launcher/internal/types"
+	"light-launcher/lib/lsfg"
+)
+
+// applyLsfg points lsfg-vk at the config WriteLsfgConfig writes for this
+// game only, so the shared conf.toml is never touched and games sharing an
+// exe name keep separate settings. When LSFG is off the implicit layer is
+// disabled outright.
 func (builder *CommandBuilder) applyLsfg() {
-	if !builder.Options.Extras.Lsfg.Enabled {
+	options := builder.Options
+	if !options.Extras.Lsfg.Enabled {
+		for key, value := range lsfg.GetDisableEnvironment() {
+			builder.Environment = append(builder.Environment, key+"="+value)
+		}
 		return
 	}
-	// Currently LSFG logic is handled by the launcher environment, 
-	// but we can add specific LSFG env vars here if needed in the future.
+
+	configPath := GetLsfgConfigPath(options)
+	builder.Environment = append(builder.Environment,
+		lsfg.ConfigEnvironment+"="+configPath,
+		lsfg.ConfigEnvironmentV2+"="+configPath,
+	)
+
+	// Without a separate launcher every Vulkan process in the session is the
+	// game, so the profile can be forced instead of matched by exe name.
+	if options.LauncherPath == "" || options.LauncherPath == options.GamePath {
+		builder.Environment = append(builder.Environment,
+			lsfg.ProcessEnvironment+"="+filepath.Base(options.GamePath),
+			lsfg.ProfileEnvironmentV2+"="+lsfgProfileName(options),
+		)
+	}
+}
+
+// WriteLsfgConfig writes the game's lsfg-vk config the launch command points
+// at. Nothing is written when LSFG is off.
+func WriteLsfgConfig(options types.LaunchOptions) error {
+	settings := options.Extras.Lsfg
+	if !settings.Enabled {
+		return nil
+	}
+
+	gpu := settings.Gpu
+	if gpu == "" {
+		if gpuList := system.GetListGpus(); len(gpuList) > 0 {
+			gpu = gpuList[0]
+		}
+	}
+	err := lsfg.SaveProfileToPath(lsfgProfileName(options), options.GamePath, GetLsfgConfigPath(options), lsfg.ParseMultiplier(settings.Multiplier),
+		settings.PerfMode, settings.DllPath, gpu, settings.FlowScale, settings.Pacing, settings.AllowFp16)
+	if err != nil {
+		return fmt.Errorf("failed to write LSFG config: %w", err)
+	}
+	return nil
+}
+
+// GetLsfgConfigPath returns the lsfg-vk config written for launches of this game.
+func GetLsfgConfigPath(options types.LaunchOptions) string {
+	if options.Name == "" && options.ID == "" {
+		return config.GetGameLsfgConfigPath(lsfgProfileName(options), "")
+	}
+	return config.GetGameLsfgConfigPath(options.Name, options.ID)
+}
+
+func lsfgProfileName(options types.LaunchOptions) string {
+	if options.ID != "" {
+		return options.ID
+	}
+	if options.Name != "" {
+		return options.Name
+	}
+	executableName := filepath.Base(options.GamePath)
+	return strings.TrimSuffix(executableName, filepath.Ext(executableName))
 }
@@ -1,7 +1,67 @@
 package builder
 
+import (
+	"fmt"
+
+	"light-launcher/internal/config"
+	"light-launcher/internal/types"
+	"light-launcher/lib/mangohud"
+)
+
+// applyMangoHud enables MangoHud and points it at the per-game config, which
+// WriteMangoHudConfig writes before the launch.
 func (builder *CommandBuilder) applyMangoHud() {
 	if builder.Options.Extras.EnableMangoHud {
 		builder.Environment = append(builder.Environment, "MANGOHUD=1")
+		if path := mangoHudConfigPath(builder.Options.Name, builder.Options.ID, builder.Options.Extras.MangoHud.PerGame); path != "" {
+			builder.Environment = append(builder.Environment, "MANGOHUD_CONFIGFILE="+path)
+		}
+	}
+}
+
+// WriteMangoHudConfig writes the per-game MangoHud.conf the launch command
+// points at. Games using the global MangoHud config need nothing written.
+func WriteMangoHudConfig(options types.LaunchOptions) error {
+	if !options.Extras.EnableMangoHud {
+		return nil
+	}
+	path := mangoHudConfigPath(options.Name, options.ID, options.Extras.MangoHud.PerGame)
+	if path == "" {
+		return nil
+	}
+	if err := mangohud.Write(path, mangoHudConfig(options.Extras.MangoHud)); err != nil {
+		return fmt.Errorf("failed to write MangoHud config: %w", err)
+	}
+	return nil
+}
+
+// mangoHudConfigPath returns where the per-game MangoHud config lives, or an
+// empty string when the game uses the global MangoHud config.
+func mangoHudConfigPath(name string, id string, perGame bool) string {
+	if !perGame || (name == "" && id == "") {
+		return ""
+	}
+	return config.GetGameMangoHudConfigPath(name, id)
+}
+
+// mangoHudConfig converts the per-game settings into the form lib/mangohud
+// renders.
+func mangoHudConfig(settings types.MangoHudConfig) mangohud.Config {
+	return mangohud.Config{
+		Preset:        settings.Preset,
+		Position:      settings.Position,
+		FpsLimit:      settings.FpsLimit,
+		ShowFps:       settings.ShowFps,
+		ShowFrametime: settings.ShowFrametime,
+		ShowCpu:       settings.ShowCpu,
+		ShowGpu:       settings.ShowGpu,
+		ShowCpuTemp:   settings.ShowCpuTemp,
+		ShowGpuTemp:   settings.ShowGpuTemp,
+		ShowRam:       settings.ShowRam,
+		ShowVram:      settings.ShowVram,
+		ShowBattery:   settings.ShowBattery,
+		LogFolder:     settings.LogFolder,
+		ExtraLines:    settings.ExtraLines,
+		Toggled:       settings.ChangedToggles,
 	}
 }
@@ -0,0 +1,97 @@
+package builder
+
+import (
+	"fmt"
+	"os"
+
+	"light-launcher/internal/config"
+	"light-launcher/internal/system"
+	"light-launcher/internal/types"
+)
+
+// protonToggle maps one ProtonOptions switch to its variable. minimumMajor
+// holds the first major version of each flavor that reads the variable; nil
+// means every build does, and a flavor missing from the map never does.
+type protonToggle struct {
+	label        string
+	variable     string
+	enabled      func(types.ProtonOptions) bool
+	minimumMajor map[string]int
+}
+
+var protonToggles = []protonToggle{
+	{"esync off", "PROTON_NO_ESYNC", func(o types.ProtonOptions) bool { return o.DisableEsync }, nil},
+	{"fsync off", "PROTON_NO_FSYNC", func(o types.ProtonOptions) bool { return o.DisableFsync }, map[string]int{
+		system.ProtonFlavorValve: 5, system.ProtonFlavorExperimental: 5, system.ProtonFlavorGE: 5, system.ProtonFlavorCachyOS: 0,
+	}},
+	{"NTSYNC", "PROTON_USE_NTSYNC", func(o types.ProtonOptions) bool { return o.EnableNtsync }, map[string]int{
+		system.ProtonFlavorValve: 11, system.ProtonFlavorExperimental: 11, system.ProtonFlavorGE: 10, system.ProtonFlavorCachyOS: 10,
+	}},
+	{"WineD3D", "PROTON_USE_WINED3D", func(o types.ProtonOptions) bool { return o.UseWineD3D }, nil},
+	{"Wayland", "PROTON_ENABLE_WAYLAND", func(o types.ProtonOptions) bool { return o.EnableWayland }, map[string]int{
+		system.ProtonFlavorValve: 10, system.ProtonFlavorExperimental: 10, system.ProtonFlavorGE: 10, system.ProtonFlavorCachyOS: 10,
+	}},
+	{"NVAPI", "PROTON_ENABLE_NVAPI", func(o types.ProtonOptions) bool { return o.EnableNvapi }, nil},
+	{"hide NVIDIA GPU", "PROTON_HIDE_NVIDIA_GPU", func(o types.ProtonOptions) bool { return o.HideNvidiaGpu }, nil},
+	{"large address aware", "PROTON_FORCE_LARGE_ADDRESS_AWARE", func(o types.ProtonOptions) bool { return o.LargeAddressAware }, nil},
+}
+
+// ProtonEnvironment translates the game's Proton options into variables for
+// the selected build. Toggles the build does not know are left out and
+// explained in the returned warnings. Builds that cannot be identified get
+// every toggle.
+func ProtonEnvironment(options types.LaunchOptions) ([][2]string, []string) {
+	protonOptions := options.Extras.Proton
+	build, known := system.ReadProtonBuild(config.ExpandPath(options.ProtonPath))
+
+	var variables [][2]string
+	var warnings []string
+	if backend := GetBackend(options).Name(); backend == BackendWine || backend == BackendNative {
+		if protonOptions != (types.ProtonOptions{}) {
+			warnings = append(warnings, fmt.Sprintf("Proton options have no effect with the %s backend", backend))
+		}
+		return nil, warnings
+	}
+	for _, toggle := range protonToggles {
+		if !toggle.enabled(protonOptions) {
+			continue
+		}
+		if known && toggle.minimumMajor != nil {
+			minimum, supported := toggle.minimumMajor[build.Flavor]
+			if !supported || build.Major < minimum {
+				warnings = append(warnings, fmt.Sprintf("%s is not supported by %s, ignoring %s", toggle.label, build.Name, toggle.variable))
+				continue
+			}
+		}
+		variables = append(variables, [2]string{toggle.variable, "1"})
+	}
+
+	if hasVariable(variables, "PROTON_USE_NTSYNC") {
+		if _, err := os.Stat("/dev/ntsync"); err != nil {
+			warnings = append(warnings, "NTSYNC needs the ntsync kernel module (/dev/ntsync is missing)")
+		}
+		if protonOptions.DisableEsync || protonOptions.DisableFsync {
+			warnings = append(warnings, "NTSYNC takes precedence, so the esync and fsync switches have no effect")
+		}
+	}
+	if hasVariable(variables, "PROTON_ENABLE_WAYLAND") && os.Getenv("WAYLAND_DISPLAY") == "" {
+		warnings = append(warnings, "Wayland was requested but WAYLAND_DISPLAY is not set")
+	}
+	return variables, warnings
+}
+
+func (builder *CommandBuilder) applyProtonOptions() {
+	variables, _ := ProtonEnvironment(builder.Options)
+	for _, variable := range variables {
+		builder.Environment = append(removeEnvironmentKey(builder.Environment, variable[0]), variable[0]+"="+variable[1])
+	}
+}
+
+func hasVariable(variables [][2]string, key string) bool {
+	for _, variable := range variables {
+		if variable[0] == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package builder
+
+import (
+	"fmt"
+	"strconv"
+
+	"light-launcher/internal/system"
+	"light-launcher/internal/types"
+)
+
+// IoniceClasses maps the ionice classes offered in the UI to their numbers.
+var IoniceClasses = map[string]string{
+	"realtime":    "1",
+	"best-effort": "2",
+	"idle":        "3",
+}
+
+// applyScheduling wraps the command in taskset, nice, ionice and chrt so the
+// settings are inherited by every process the game starts. Missing tools are
+// skipped.
+func (builder *CommandBuilder) applyScheduling() {
+	wrappedArguments := schedulingWrappers(builder.Options.Extras.Scheduling)
+	if len(wrappedArguments) > 0 {
+		builder.Arguments = append(wrappedArguments, builder.Arguments...)
+	}
+}
+
+func schedulingWrappers(scheduling types.SchedulingConfig) []string {
+	if !scheduling.Enabled {
+		return nil
+	}
+
+	var arguments []string
+	if scheduling.CpuSet != "" && system.IsCommandAvailable("taskset") {
+		arguments = append(arguments, "taskset", "-c", scheduling.CpuSet)
+	}
+	if scheduling.Nice != "" && system.IsCommandAvailable("nice") {
+		arguments = append(arguments, "nice", "-n", scheduling.Nice)
+	}
+	if class := IoniceClasses[scheduling.IoniceClass]; class != "" && system.IsCommandAvailable("ionice") {
+		arguments = append(arguments, "ionice", "-c", class)
+		// Only the realtime and best-effort classes take a level.
+		if scheduling.IoniceLevel != "" && class != "3" {
+			arguments = append(arguments, "-n", scheduling.IoniceLevel)
+		}
+	}
+	if scheduling.IdlePriority && system.IsCommandAvailable("chrt") {
+		arguments = append(arguments, "chrt", "--idle", "0")
+	}
+	return arguments
+}
+
+// schedulingWrapperNames lists the programs schedulingWrappers adds, for
+// WrapperChain.
+func schedulingWrapperNames(scheduling types.SchedulingConfig) []string {
+	var names []string
+	arguments := schedulingWrappers(scheduling)
+	for _, program := range []string{"taskset", "nice", "ionice", "chrt"} {
+		for _, argument := range arguments {
+			if argument == program {
+				names = append(names, program)
+				break
+			}
+		}
+	}
+	return names
+}
+
+// SchedulingWarnings describes settings that will not take effect for the
+// current user. nice still starts the game when it cannot raise priority.
+func SchedulingWarnings(scheduling types.SchedulingConfig) []string {
+	if !scheduling.Enabled || scheduling.Nice == "" {
+		return nil
+	}
+	if value, err := strconv.Atoi(scheduling.Nice); err == nil && !system.CanSetNice(value) {
+		return []string{fmt.Sprintf("nice %d needs CAP_SYS_NICE or a higher RLIMIT_NICE, the game runs at normal priority", value)}
+	}
+	return nil
+}
+
+// ValidateScheduling rejects values the wrappers would refuse.
+func ValidateScheduling(extras types.ExtrasConfig) error {
+	scheduling := extras.Scheduling
+	if !scheduling.Enabled {
+		return nil
+	}
+
+	if scheduling.CpuSet != "" && !cpuListPattern.MatchString(scheduling.CpuSet) {
+		return fmt.Errorf("CPU set must be a CPU list like 0-7,16-23, got %q", scheduling.CpuSet)
+	}
+	if scheduling.Nice != "" {
+		if value, err := strconv.Atoi(scheduling.Nice); err != nil || value < -20 || value > 19 {
+			return fmt.Errorf("nice level must be between -20 and 19, got %q", scheduling.Nice)
+		}
+	}
+	if scheduling.IoniceClass != "" && IoniceClasses[scheduling.IoniceClass] == "" {
+		return fmt.Errorf("unknown ionice class %q", scheduling.IoniceClass)
+	}
+	if scheduling.IoniceClass == "realtime" && !system.HasCapability(system.CapSysAdmin) {
+		return fmt.Errorf("the realtime ionice class needs CAP_SYS_ADMIN, use best-effort instead")
+	}
+	if scheduling.IoniceLevel != "" {
+		if value, err := strconv.Atoi(scheduling.IoniceLevel); err != nil || value < 0 || value > 7 {
+			return fmt.Errorf("ionice level must be between 0 and 7, got %q", scheduling.IoniceLevel)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package builder
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"light-launcher/internal/system"
+	"light-launcher/internal/types"
+)
+
+// ScopeUnitPrefix starts the name of every scope a session runs in.
+const ScopeUnitPrefix = "light-launcher-"
+
+const scopeUnitFlag = "--unit="
+
+var (
+	unitNameCharacters = regexp.MustCompile(`[^A-Za-z0-9:_.\-]+`)
+	memoryValuePattern = regexp.MustCompile(`^(\d+(\.\d+)?[KMGT]?|\d+(\.\d+)?%|infinity)$`)
+	cpuQuotaPattern    = regexp.MustCompile(`^\d+(\.\d+)?%$`)
+	cpuListPattern     = regexp.MustCompile(`^\d+(-\d+)?(,\d+(-\d+)?)*$`)
+)
+
+// usesScope reports whether the game runs in its own systemd scope. Without
+// a systemd user manager the game starts directly and the limits are skipped.
+func usesScope(options types.LaunchOptions) bool {
+	memory := options.Extras.Memory
+	if !options.Extras.Scope.Enabled && !(memory.Enabled && memory.Value != "") {
+		return false
+	}
+	return system.IsSystemdUserAvailable()
+}
+
+// ScopeUnitName is the base name of the game's scope unit, without the
+// .scope suffix.
+func ScopeUnitName(options types.LaunchOptions) string {
+	name := options.ID
+	if name == "" {
+		name = options.Name
+	}
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(options.GamePath), filepath.Ext(options.GamePath))
+	}
+	return ScopeUnitPrefix + strings.Trim(unitNameCharacters.ReplaceAllString(name, "_"), "_")
+}
+
+func (builder *CommandBuilder) applyScope() {
+	if !usesScope(builder.Options) {
+		return
+	}
+
+	// A second session of the same game cannot reuse the running scope's name.
+	unit := ScopeUnitName(builder.Options)
+	for index := 2; system.IsUserUnitActive(unit + ".scope"); index++ {
+		unit = fmt.Sprintf("%s-%d", ScopeUnitName(builder.Options), index)
+	}
+
+	wrappedArguments := []string{
+		"systemd-run",
+		"--user",
+		"--scope",
+		"--collect",
+		scopeUnitFlag + unit,
+	}
+	for _, property := range scopeProperties(builder.Options.Extras) {
+		wrappedArguments = append(wrappedArguments, "-p"+property)
+	}
+	wrappedArguments = append(wrappedArguments, "--")
+	builder.Arguments = append(wrappedArguments, builder.Arguments...)
+}
+
+func scopeProperties(extras types.ExtrasConfig) []string {
+	var properties []string
+	addProperty := func(name, value string) {
+		if value != "" {
+			properties = append(properties, name+"="+value)
+		}
+	}
+
+	if extras.Memory.Enabled {
+		addProperty("MemoryMin", extras.Memory.Value)
+	}
+	if scope := extras.Scope; scope.Enabled {
+		addProperty("MemoryHigh", scope.MemoryHigh)
+		addProperty("MemoryMax", scope.MemoryMax)
+		addProperty("CPUQuota", scope.CPUQuota)
+		addProperty("CPUWeight", scope.CPUWeight)
+		addProperty("IOWeight", scope.IOWeight)
+		addProperty("AllowedCPUs", scope.AllowedCPUs)
+	}
+	return properties
+}
+
+// ValidateScope rejects limits systemd-run would refuse, which would
+// otherwise stop the game from starting at all.
+func ValidateScope(extras types.ExtrasConfig) error {
+	scope := extras.Scope
+	if !scope.Enabled {
+		return nil
+	}
+
+	memoryValues := []struct{ name, value string }{
+		{"MemoryMax", scope.MemoryMax},
+		{"MemoryHigh", scope.MemoryHigh},
+	}
+	for _, memory := range memoryValues {
+		if memory.value != "" && !memoryValuePattern.MatchString(memory.value) {
+			return fmt.Errorf("%s must be a size like 8G or a percentage, got %q", memory.name, memory.value)
+		}
+	}
+	if scope.CPUQuota != "" && !cpuQuotaPattern.MatchString(scope.CPUQuota) {
+		return fmt.Errorf("CPUQuota must be a percentage like 200%%, got %q", scope.CPUQuota)
+	}
+
+	weights := []struct{ name, value string }{
+		{"CPUWeight", scope.CPUWeight},
+		{"IOWeight", scope.IOWeight},
+	}
+	for _, weight := range weights {
+		if weight.value == "" {
+			continue
+		}
+		if value, err := strconv.Atoi(weight.value); err != nil || value < 1 || value > 10000 {
+			return fmt.Errorf("%s must be between 1 and 10000, got %q", weight.name, weight.value)
+		}
+	}
+
+	if scope.AllowedCPUs != "" && !cpuListPattern.MatchString(scope.AllowedCPUs) {
+		return fmt.Errorf("AllowedCPUs must be a CPU list like 0-3,6, got %q", scope.AllowedCPUs)
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package builder
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"light-launcher/internal/config"
+	"light-launcher/internal/executor"
+	"light-launcher/internal/types"
+)
+
+// BuildLaunchScript renders a standalone POSIX shell script that reproduces
+// the environment and wrapper chain BuildCommand produces for options.
+func BuildLaunchScript(options types.LaunchOptions) string {
+	arguments, environment := buildCommand(&CommandBuilder{Options: options})
+
+	name := options.Name
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(options.GamePath), filepath.Ext(options.GamePath))
+	}
+
+	var script strings.Builder
+	script.WriteString("#!/bin/sh\n")
+	fmt.Fprintf(&script, "# LightLauncher launch script for %s\n", strings.ReplaceAll(name, "\n", " "))
+	fmt.Fprintf(&script, "# Generated %s. Export again after changing the game's settings.\n\n", time.Now().Format("2006-01-02 15:04:05"))
+
+	// Every launch variable is exported, even when it matches the current
+	// environment, since the script may run from a different one.
+	unset, exported := scriptEnvironment(options, environment)
+	for _, key := range unset {
+		fmt.Fprintf(&script, "unset %s\n", QuoteShellWord(key))
+	}
+	for _, assignment := range exported {
+		key, value, _ := strings.Cut(assignment, "=")
+		fmt.Fprintf(&script, "export %s=%s\n", key, QuoteShellWord(value))
+	}
+	if len(unset) > 0 || len(exported) > 0 {
+		script.WriteString("\n")
+	}
+
+	if usesScope(options) {
+		writeScopeUnitProbe(&script, options)
+	}
+
+	// Mirrors protonBackend.Prepare for prefixes not launched before.
+	if GetBackend(options).Name() == BackendProton && compatDataPath(options) == config.ExpandPath(options.PrefixPath) {
+		script.WriteString("[ -e \"$STEAM_COMPAT_DATA_PATH/pfx\" ] || ln -s . \"$STEAM_COMPAT_DATA_PATH/pfx\"\n\n")
+	}
+
+	if len(options.PreLaunchHooks) == 0 && len(options.PostExitHooks) == 0 {
+		fmt.Fprintf(&script, "exec %s \"$@\"\n", scriptCommand(options, arguments))
+		return script.String()
+	}
+
+	writeScriptHooks(&script, options, arguments)
+	return script.String()
+}
+
+// writeScopeUnitProbe picks the scope name when the script runs, like
+// applyScope does at launch, so the script works while the game is already
+// running and when started twice.
+func writeScopeUnitProbe(script *strings.Builder, options types.LaunchOptions) {
+	base := QuoteShellWord(ScopeUnitName(options))
+	fmt.Fprintf(script, "unit=%s\n", base)
+	script.WriteString("index=2\n")
+	script.WriteString("while systemctl --user --quiet is-active \"$unit.scope\"; do\n")
+	fmt.Fprintf(script, "\tunit=%s-$index\n", base)
+	script.WriteString("\tindex=$((index + 1))\n")
+	script.WriteString("done\n\n")
+}
+
+// scriptCommand quotes arguments for the script, naming the scope after the
+// unit variable writeScopeUnitProbe sets.
+func scriptCommand(options types.LaunchOptions, arguments []string) string {
+	words := make([]string, len(arguments))
+	for index, argument := range arguments {
+		words[index] = QuoteShellWord(argument)
+	}
+	if usesScope(options) {
+		for index, argument := range arguments {
+			if argument == "--" {
+				break
+			}
+			if strings.HasPrefix(argument, scopeUnitFlag) {
+				words[index] = scopeUnitFlag + `"$unit"`
+				break
+			}
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// writeScriptHooks runs the command between its pre-launch and post-exit
+// hooks, mirroring what light-launcher-instance does.
+func writeScriptHooks(script *strings.Builder, options types.LaunchOptions, arguments []string) {
+	session := executor.HookSession{Options: options}
+	for _, assignment := range session.Environment() {
+		key, value, _ := strings.Cut(assignment, "=")
+		if strings.HasPrefix(key, "LIGHT_LAUNCHER_SESSION_") {
+			fmt.Fprintf(script, "export %s=%s\n", key, QuoteShellWord(value))
+		}
+	}
+
+	// Each hook runs in its own process group, which is killed as a whole on
+	// timeout like executor.RunHook does.
+	script.WriteString("\nrun_hook() {\n")
+	script.WriteString("\tLIGHT_LAUNCHER_HOOK_STAGE=\"$1\" setsid sh -c \"$3\" &\n")
+	script.WriteString("\thook_pid=$!\n")
+	script.WriteString("\tsetsid sh -c 'sleep \"$1\"; kill -KILL -- \"-$2\"' sh \"$2\" \"$hook_pid\" 2>/dev/null &\n")
+	script.WriteString("\thook_watchdog=$!\n")
+	script.WriteString("\twait \"$hook_pid\"\n")
+	script.WriteString("\thook_status=$?\n")
+	script.WriteString("\tkill -- \"-$hook_watchdog\" 2>/dev/null\n")
+	script.WriteString("\treturn $hook_status\n")
+	script.WriteString("}\n\n")
+
+	for _, hook := range options.PreLaunchHooks {
+		if hook.Command == "" {
+			continue
+		}
+		line := fmt.Sprintf("run_hook %s %d %s", executor.HookStagePreLaunch, hookTimeoutSeconds(hook), QuoteShellWord(hook.Command))
+		if hook.AbortOnFailure {
+			line += " || exit 1"
+		}
+		script.WriteString(line + "\n")
+	}
+
+	fmt.Fprintf(script, "\n%s \"$@\"\n", scriptCommand(options, arguments))
+	script.WriteString("status=$?\n\n")
+
+	script.WriteString("export LIGHT_LAUNCHER_SESSION_EXIT_CODE=$status\n")
+	for _, hook := range options.PostExitHooks {
+		if hook.Command != "" {
+			fmt.Fprintf(script, "run_hook %s %d %s\n", executor.HookStagePostExit, hookTimeoutSeconds(hook), QuoteShellWord(hook.Command))
+		}
+	}
+	script.WriteString("exit $status\n")
+}
+
+func hookTimeoutSeconds(hook types.HookCommand) int {
+	if hook.TimeoutSeconds > 0 {
+		return hook.TimeoutSeconds
+	}
+	return int(executor.DefaultHookTimeout.Seconds())
+}
+
+// WriteLaunchScript writes the launch script for options to path and marks it
+// executable, along with the per-game configs the script points at.
+func WriteLaunchScript(path string, options types.LaunchOptions) error {
+	if err := ValidateCustomArgs(options.CustomArgs); err != nil {
+		return err
+	}
+	if err := WriteMangoHudConfig(options); err != nil {
+		return err
+	}
+	if err := WriteLsfgConfig(options); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(BuildLaunchScript(options)), 0755)
+}
+
+// scriptEnvironment returns the variables the game's settings unset and the
+// KEY=VALUE launch variables to export, in launch order. Later entries win,
+// matching how exec.Cmd treats duplicate keys.
+func scriptEnvironment(options types.LaunchOptions, launchEnvironment []string) (unset []string, exported []string) {
+	values := environmentToMap(launchEnvironment)
+	for _, variable := range options.Environment {
+		key := strings.TrimSpace(variable.Key)
+		if _, set := values[key]; variable.Unset && !set && !contains(unset, key) {
+			unset = append(unset, key)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, entry := range launchEnvironment {
+		key, _, _ := strings.Cut(entry, "=")
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		exported = append(exported, key+"="+values[key])
+	}
+	return unset, exported
+}
+
+func environmentToMap(environment []string) map[string]string {
+	values := make(map[string]string, len(environment))
+	for _, entry := range environment {
+		key, value, found := strings.Cut(entry, "=")
+		if found {
+			values[key] = value
+		}
+	}
+	return values
+}
+
+func contains(slice []string, search string) bool {
+	for _, item := range slice {
+		if item == search {
+			return true
+		}
+	}
+	return false
+}
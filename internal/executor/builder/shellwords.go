@@ -0,0 +1,160 @@
+package builder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CommandPlaceholder marks where the generated command goes inside CustomArgs,
+// following Steam's launch options syntax.
+const CommandPlaceholder = "%command%"
+
+// SplitShellWords splits a string into words using POSIX shell quoting rules.
+// Single quotes are literal, double quotes allow \" \\ \$ and \` escapes, and
+// a backslash outside quotes escapes the next character.
+func SplitShellWords(input string) ([]string, error) {
+	var words []string
+	var current strings.Builder
+	inWord := false
+
+	runes := []rune(input)
+	for index := 0; index < len(runes); index++ {
+		character := runes[index]
+
+		switch {
+		case character == '\\':
+			if index+1 >= len(runes) {
+				return nil, fmt.Errorf("trailing backslash")
+			}
+			index++
+			if runes[index] != '\n' {
+				current.WriteRune(runes[index])
+			}
+			inWord = true
+
+		case character == '\'':
+			end := index + 1
+			for end < len(runes) && runes[end] != '\'' {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unterminated single quote")
+			}
+			current.WriteString(string(runes[index+1 : end]))
+			index = end
+			inWord = true
+
+		case character == '"':
+			index++
+			for ; index < len(runes) && runes[index] != '"'; index++ {
+				if runes[index] == '\\' && index+1 < len(runes) {
+					switch runes[index+1] {
+					case '"', '\\', '$', '`':
+						index++
+					case '\n':
+						index++
+						continue
+					}
+				}
+				current.WriteRune(runes[index])
+			}
+			if index >= len(runes) {
+				return nil, fmt.Errorf("unterminated double quote")
+			}
+			inWord = true
+
+		case character == ' ' || character == '\t' || character == '\n':
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+				inWord = false
+			}
+
+		default:
+			current.WriteRune(character)
+			inWord = true
+		}
+	}
+
+	if inWord {
+		words = append(words, current.String())
+	}
+	return words, nil
+}
+
+// QuoteShellWord returns word quoted so a POSIX shell reads it back unchanged.
+func QuoteShellWord(word string) string {
+	if word == "" {
+		return "''"
+	}
+	safe := true
+	for _, character := range word {
+		if !isShellSafe(character) {
+			safe = false
+			break
+		}
+	}
+	if safe {
+		return word
+	}
+	return "'" + strings.ReplaceAll(word, "'", `'\''`) + "'"
+}
+
+// JoinShellWords quotes each word and joins them with spaces.
+func JoinShellWords(words []string) string {
+	quoted := make([]string, len(words))
+	for index, word := range words {
+		quoted[index] = QuoteShellWord(word)
+	}
+	return strings.Join(quoted, " ")
+}
+
+func isShellSafe(character rune) bool {
+	switch {
+	case character >= 'a' && character <= 'z',
+		character >= 'A' && character <= 'Z',
+		character >= '0' && character <= '9':
+		return true
+	}
+	return strings.ContainsRune("-_./:=@%+,", character)
+}
+
+// splitCustomArgs separates CustomArgs around %command%. Without the
+// placeholder every word is an argument for the game.
+func splitCustomArgs(customArgs string) (prefix []string, suffix []string, err error) {
+	words, err := SplitShellWords(customArgs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for index, word := range words {
+		if word == CommandPlaceholder {
+			return words[:index], words[index+1:], nil
+		}
+	}
+	return nil, words, nil
+}
+
+// ValidateCustomArgs rejects custom arguments that do not parse as shell
+// words, such as an unterminated quote.
+func ValidateCustomArgs(customArgs string) error {
+	if _, _, err := splitCustomArgs(customArgs); err != nil {
+		return fmt.Errorf("invalid launch arguments: %w", err)
+	}
+	return nil
+}
+
+// isEnvironmentAssignment reports whether word looks like NAME=value.
+func isEnvironmentAssignment(word string) bool {
+	name, _, found := strings.Cut(word, "=")
+	if !found || name == "" {
+		return false
+	}
+	for index, character := range name {
+		isLetter := character == '_' || (character >= 'a' && character <= 'z') || (character >= 'A' && character <= 'Z')
+		if !isLetter && (index == 0 || character < '0' || character > '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,140 @@
+package builder
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitShellWords(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{name: "empty", input: "", want: nil},
+		{name: "spaces", input: "  -a \t -b\n-c  ", want: []string{"-a", "-b", "-c"}},
+		{name: "single quotes", input: `'two words' -x`, want: []string{"two words", "-x"}},
+		{name: "double quotes", input: `"two words" -x`, want: []string{"two words", "-x"}},
+		{name: "adjacent quotes join", input: `a'b c'"d e"f`, want: []string{"ab cd ef"}},
+		{name: "empty quotes", input: `'' ""`, want: []string{"", ""}},
+		{name: "double inside single", input: `'say "hi"'`, want: []string{`say "hi"`}},
+		{name: "single inside double", input: `"it's"`, want: []string{"it's"}},
+		{name: "backslash in single quotes", input: `'a\b'`, want: []string{`a\b`}},
+		{name: "escaped space", input: `a\ b c`, want: []string{"a b", "c"}},
+		{name: "escaped quotes", input: `\"a\' \\`, want: []string{`"a'`, `\`}},
+		{name: "double quote escapes", input: `"\" \\ \$ \` + "`" + `"`, want: []string{`" \ $ ` + "`"}},
+		{name: "other escapes kept in double quotes", input: `"\n\a"`, want: []string{`\n\a`}},
+		{name: "line continuation", input: "a\\\nb \"c\\\nd\"", want: []string{"ab", "cd"}},
+		{name: "assignment with quoted value", input: `DXVK_HUD="fps,memory" %command%`, want: []string{"DXVK_HUD=fps,memory", "%command%"}},
+		{name: "unicode", input: `"héllo wörld" ünï`, want: []string{"héllo wörld", "ünï"}},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := SplitShellWords(testCase.input)
+			if err != nil {
+				t.Fatalf("SplitShellWords(%q) failed: %v", testCase.input, err)
+			}
+			if !reflect.DeepEqual(got, testCase.want) {
+				t.Errorf("SplitShellWords(%q) = %q, want %q", testCase.input, got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestSplitShellWordsErrors(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "unterminated single quote", input: `-a 'b c`, want: "unterminated single quote"},
+		{name: "unterminated double quote", input: `-a "b c`, want: "unterminated double quote"},
+		{name: "escaped closing double quote", input: `"b\"`, want: "unterminated double quote"},
+		{name: "double quote inside single", input: `'a" b`, want: "unterminated single quote"},
+		{name: "trailing backslash", input: `a \`, want: "trailing backslash"},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			words, err := SplitShellWords(testCase.input)
+			if err == nil {
+				t.Fatalf("SplitShellWords(%q) = %q, want error %q", testCase.input, words, testCase.want)
+			}
+			if err.Error() != testCase.want {
+				t.Errorf("SplitShellWords(%q) error = %q, want %q", testCase.input, err, testCase.want)
+			}
+		})
+	}
+}
+
+func TestQuoteShellWordRoundTrip(t *testing.T) {
+	words := []string{"", "plain", "two words", "it's", `"quoted"`, `back\slash`, "$HOME", "a`b`", "tab\there", "new\nline", "%command%", "ünï"}
+	for _, word := range words {
+		got, err := SplitShellWords(QuoteShellWord(word))
+		if err != nil {
+			t.Errorf("QuoteShellWord(%q) = %s does not parse: %v", word, QuoteShellWord(word), err)
+			continue
+		}
+		if len(got) != 1 || got[0] != word {
+			t.Errorf("QuoteShellWord(%q) = %s reads back as %q", word, QuoteShellWord(word), got)
+		}
+	}
+
+	joined, err := SplitShellWords(JoinShellWords(words))
+	if err != nil {
+		t.Fatalf("JoinShellWords output does not parse: %v", err)
+	}
+	if !reflect.DeepEqual(joined, words) {
+		t.Errorf("JoinShellWords reads back as %q, want %q", joined, words)
+	}
+}
+
+func TestSplitCustomArgs(t *testing.T) {
+	cases := []struct {
+		name       string
+		customArgs string
+		wantPrefix []string
+		wantSuffix []string
+	}{
+		{name: "no placeholder", customArgs: `-windowed -height 1080`, wantSuffix: []string{"-windowed", "-height", "1080"}},
+		{name: "placeholder only", customArgs: `%command%`, wantPrefix: []string{}, wantSuffix: []string{}},
+		{name: "placeholder first", customArgs: `%command% -dx11`, wantPrefix: []string{}, wantSuffix: []string{"-dx11"}},
+		{name: "placeholder last", customArgs: `PROTON_LOG=1 gamemoderun %command%`, wantPrefix: []string{"PROTON_LOG=1", "gamemoderun"}, wantSuffix: []string{}},
+		{name: "placeholder in middle", customArgs: `VAR="a b" strace -f %command% -skip 'intro video'`, wantPrefix: []string{"VAR=a b", "strace", "-f"}, wantSuffix: []string{"-skip", "intro video"}},
+		{name: "quoted placeholder", customArgs: `env '%command%' -x`, wantPrefix: []string{"env"}, wantSuffix: []string{"-x"}},
+		{name: "placeholder inside word", customArgs: `--run=%command%`, wantSuffix: []string{"--run=%command%"}},
+		{name: "first placeholder wins", customArgs: `a %command% b %command%`, wantPrefix: []string{"a"}, wantSuffix: []string{"b", "%command%"}},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			prefix, suffix, err := splitCustomArgs(testCase.customArgs)
+			if err != nil {
+				t.Fatalf("splitCustomArgs(%q) failed: %v", testCase.customArgs, err)
+			}
+			if len(prefix) != len(testCase.wantPrefix) || (len(prefix) > 0 && !reflect.DeepEqual(prefix, testCase.wantPrefix)) {
+				t.Errorf("splitCustomArgs(%q) prefix = %q, want %q", testCase.customArgs, prefix, testCase.wantPrefix)
+			}
+			if len(suffix) != len(testCase.wantSuffix) || (len(suffix) > 0 && !reflect.DeepEqual(suffix, testCase.wantSuffix)) {
+				t.Errorf("splitCustomArgs(%q) suffix = %q, want %q", testCase.customArgs, suffix, testCase.wantSuffix)
+			}
+		})
+	}
+}
+
+func TestValidateCustomArgs(t *testing.T) {
+	valid := []string{"", "-windowed", `VAR='x y' %command% "-a b"`}
+	for _, customArgs := range valid {
+		if err := ValidateCustomArgs(customArgs); err != nil {
+			t.Errorf("ValidateCustomArgs(%q) = %v, want nil", customArgs, err)
+		}
+	}
+
+	invalid := []string{`-name "unterminated`, `%command% 'open`, `trailing \`}
+	for _, customArgs := range invalid {
+		if err := ValidateCustomArgs(customArgs); err == nil {
+			t.Errorf("ValidateCustomArgs(%q) = nil, want an error", customArgs)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package executor
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DebugPreset selects the Wine channels and DXVK verbosity of a debug launch.
+type DebugPreset struct {
+	Name         string
+	WineDebug    string
+	DxvkLogLevel string
+}
+
+// debugBaseChannels are the channels PROTON_LOG enables by default.
+const debugBaseChannels = "+timestamp,+pid,+tid,+seh,+debugstr,+loaddll,+mscoree"
+
+// DebugPresets extend the channels Proton logs by default with the ones
+// relevant to a kind of problem.
+var DebugPresets = []DebugPreset{
+	{"crash", debugBaseChannels, "info"},
+	{"graphics", debugBaseChannels + ",+d3d,+vulkan,+wgl", "debug"},
+	{"input", debugBaseChannels + ",+dinput,+hid,+xinput,+rawinput", "info"},
+	{"audio", debugBaseChannels + ",+mmdevapi,+xaudio2,+dsound,+winepulse", "info"},
+	{"network", debugBaseChannels + ",+winsock,+winhttp,+wininet,+secur32", "info"},
+}
+
+func FindDebugPreset(name string) (DebugPreset, bool) {
+	for _, preset := range DebugPresets {
+		if preset.Name == name {
+			return preset, true
+		}
+	}
+	return DebugPreset{}, false
+}
+
+// GetDebugDirectory is where a debug launch collects Proton, DXVK and
+// vkd3d-proton logs next to the session log.
+func GetDebugDirectory(logPath string) string {
+	return strings.TrimSuffix(logPath, filepath.Ext(logPath)) + "-debug"
+}
+
+// DebugEnvironment returns the variables of a debug launch that writes its
+// logs to directory.
+func DebugEnvironment(preset DebugPreset, directory string) []string {
+	return []string{
+		"PROTON_LOG=1",
+		"PROTON_LOG_DIR=" + directory,
+		"WINEDEBUG=" + preset.WineDebug,
+		"DXVK_LOG_LEVEL=" + preset.DxvkLogLevel,
+		"DXVK_LOG_PATH=" + directory,
+		"VKD3D_DEBUG=" + vkd3dLogLevel(preset.DxvkLogLevel),
+		"VKD3D_LOG_FILE=" + filepath.Join(directory, "vkd3d.log"),
+		"UMU_LOG=debug",
+	}
+}
+
+func vkd3dLogLevel(dxvkLevel string) string {
+	if dxvkLevel == "debug" {
+		return "trace"
+	}
+	return "warn"
+}
+
+// BundleDebugLogs packs the session log and everything in the debug
+// directory into a .tar.gz next to the log, then removes the directory. A
+// partly written archive is removed when bundling fails.
+func BundleDebugLogs(logPath string) (string, error) {
+	directory := GetDebugDirectory(logPath)
+	bundlePath := directory + ".tar.gz"
+
+	file, err := os.Create(bundlePath)
+	if err != nil {
+		return "", err
+	}
+	if err := writeDebugBundle(file, logPath, directory); err != nil {
+		file.Close()
+		_ = os.Remove(bundlePath)
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		_ = os.Remove(bundlePath)
+		return "", err
+	}
+
+	_ = os.RemoveAll(directory)
+	return bundlePath, nil
+}
+
+func writeDebugBundle(file io.Writer, logPath, directory string) error {
+	compressor := gzip.NewWriter(file)
+	archive := tar.NewWriter(compressor)
+
+	files := []string{logPath}
+	if entries, err := os.ReadDir(directory); err == nil {
+		var names []string
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				names = append(names, entry.Name())
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			files = append(files, filepath.Join(directory, name))
+		}
+	}
+
+	for _, path := range files {
+		if err := addToArchive(archive, path); err != nil {
+			return fmt.Errorf("failed to add %s: %w", filepath.Base(path), err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	return compressor.Close()
+}
+
+func addToArchive(archive *tar.Writer, path string) error {
+	source, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	info, err := source.Stat()
+	if err != nil {
+		return err
+	}
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	header.Name = filepath.Base(path)
+	if err := archive.WriteHeader(header); err != nil {
+		return err
+	}
+	// Copy no more than the header announced, in case something still
+	// appends to the file.
+	_, err = io.CopyN(archive, source, header.Size)
+	return err
+}
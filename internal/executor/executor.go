@@ -80,13 +80,3 @@ func RunGameWithLog(commandArguments []string, environment []string, onLog func(
 
 	return command.Process, nil
 }
-
-func StopProcessGroup(process *os.Process) error {
-	if process == nil {
-		return nil
-	}
-	processGroupId := -process.Pid
-	_ = syscall.Kill(processGroupId, syscall.SIGINT)
-	time.Sleep(200 * time.Millisecond)
-	return syscall.Kill(processGroupId, syscall.SIGTERM)
-}
@@ -0,0 +1,113 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+
+	"light-launcher/internal/config"
+	"light-launcher/internal/types"
+)
+
+const (
+	HookStagePreLaunch = "pre-launch"
+	HookStagePostExit  = "post-exit"
+
+	DefaultHookTimeout = 60 * time.Second
+)
+
+// HookSession describes the launch a hook runs for. It is exposed to hooks
+// as LIGHT_LAUNCHER_* environment variables. WinePrefix is the WINEPREFIX
+// the launch backend sets and is left out when the game does not run under
+// Wine.
+type HookSession struct {
+	Stage      string
+	Options    types.LaunchOptions
+	LogPath    string
+	WinePrefix string
+	ExitCode   int
+}
+
+func (session HookSession) Environment() []string {
+	environment := append(os.Environ(),
+		"LIGHT_LAUNCHER_HOOK_STAGE="+session.Stage,
+		"LIGHT_LAUNCHER_SESSION_GAME="+session.Options.GamePath,
+		"LIGHT_LAUNCHER_SESSION_LAUNCHER="+session.Options.LauncherPath,
+		"LIGHT_LAUNCHER_SESSION_NAME="+session.Options.Name,
+		"LIGHT_LAUNCHER_SESSION_ID="+session.Options.ID,
+		"LIGHT_LAUNCHER_SESSION_PREFIX="+config.ExpandPath(session.Options.PrefixPath),
+		"LIGHT_LAUNCHER_SESSION_PROTON="+config.ExpandPath(session.Options.ProtonPath),
+		"LIGHT_LAUNCHER_SESSION_LOG="+session.LogPath,
+	)
+	if session.WinePrefix != "" {
+		environment = append(environment, "WINEPREFIX="+session.WinePrefix)
+	}
+	if session.Stage == HookStagePostExit {
+		environment = append(environment, fmt.Sprintf("LIGHT_LAUNCHER_SESSION_EXIT_CODE=%d", session.ExitCode))
+	}
+	return environment
+}
+
+// RunHook runs hook.Command through sh -c, writing its output to output. The
+// whole process group is killed when the timeout expires.
+func RunHook(hook types.HookCommand, session HookSession, output io.Writer) error {
+	if hook.Command == "" {
+		return nil
+	}
+
+	timeout := DefaultHookTimeout
+	if hook.TimeoutSeconds > 0 {
+		timeout = time.Duration(hook.TimeoutSeconds) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	command := exec.CommandContext(ctx, "sh", "-c", hook.Command)
+	command.Env = session.Environment()
+	command.Stdout = output
+	command.Stderr = output
+	command.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	command.Cancel = func() error {
+		return syscall.Kill(-command.Process.Pid, syscall.SIGKILL)
+	}
+	command.WaitDelay = 2 * time.Second
+
+	err := command.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s", timeout)
+	}
+	return err
+}
+
+// ExitCode extracts the exit status from the error returned by Cmd.Wait.
+// Processes killed by a signal report 128 plus the signal number, like a shell.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitError *exec.ExitError
+	if errors.As(err, &exitError) {
+		if status, ok := exitError.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+			return 128 + int(status.Signal())
+		}
+		return exitError.ExitCode()
+	}
+	return -1
+}
+
+// ExitSignal names the signal that killed the process, or returns an empty
+// string when it exited on its own.
+func ExitSignal(err error) string {
+	var exitError *exec.ExitError
+	if errors.As(err, &exitError) {
+		if status, ok := exitError.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+			return status.Signal().String()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,178 @@
+package executor
+
+import (
+	"encoding/json"
+	"path/filepath"
+
+	"light-launcher/internal/types"
+)
+
+// ResolveGamePath points GamePath at the launcher unless the game's own
+// executable was explicitly chosen.
+func ResolveGamePath(options types.LaunchOptions) types.LaunchOptions {
+	if !options.UseGamePath && options.LauncherPath != "" {
+		options.GamePath = options.LauncherPath
+	}
+	return options
+}
+
+// BuildInstanceManagerArgs converts launch options into light-launcher-instance flags.
+func BuildInstanceManagerArgs(options types.LaunchOptions, showLogs bool) []string {
+	arguments := []string{
+		"--game", options.GamePath,
+		"--launcher", options.LauncherPath,
+		"--prefix", options.PrefixPath,
+		"--proton-pattern", filepath.Base(options.ProtonPath),
+		"--proton-path", options.ProtonPath,
+	}
+	if options.Backend != "" {
+		arguments = append(arguments, "--backend", options.Backend)
+	}
+	if options.WinePath != "" {
+		arguments = append(arguments, "--wine-path", options.WinePath)
+	}
+	if options.ID != "" {
+		arguments = append(arguments, "--game-id", options.ID)
+	}
+	if options.Name != "" {
+		arguments = append(arguments, "--name", options.Name)
+	}
+	if options.CustomArgs != "" {
+		arguments = append(arguments, "--args", options.CustomArgs)
+	}
+	if options.UmuGameID != "" {
+		arguments = append(arguments, "--umu-id", options.UmuGameID)
+	}
+	if options.UmuStore != "" {
+		arguments = append(arguments, "--umu-store", options.UmuStore)
+	}
+	for _, variable := range options.Environment {
+		if variable.Key == "" {
+			continue
+		}
+		if variable.Unset {
+			arguments = append(arguments, "--unset-env", variable.Key)
+		} else {
+			arguments = append(arguments, "--env", variable.Key+"="+variable.Value)
+		}
+	}
+	for _, hook := range options.PreLaunchHooks {
+		if data, err := json.Marshal(hook); err == nil && hook.Command != "" {
+			arguments = append(arguments, "--pre-launch-hook", string(data))
+		}
+	}
+	for _, hook := range options.PostExitHooks {
+		if data, err := json.Marshal(hook); err == nil && hook.Command != "" {
+			arguments = append(arguments, "--post-exit-hook", string(data))
+		}
+	}
+	if options.Extras.EnableMangoHud {
+		arguments = append(arguments, "--mango")
+		if options.Extras.MangoHud.PerGame {
+			if data, err := json.Marshal(options.Extras.MangoHud); err == nil {
+				arguments = append(arguments, "--mango-config", string(data))
+			}
+		}
+	}
+	if options.Extras.EnableGamemode {
+		arguments = append(arguments, "--gamemode")
+	}
+	if options.Extras.GpuDevice != "" {
+		arguments = append(arguments, "--gpu", options.Extras.GpuDevice)
+	}
+	if options.Extras.Lsfg.Enabled {
+		arguments = append(arguments, "--lsfg", "--lsfg-mult", options.Extras.Lsfg.Multiplier)
+		if options.Extras.Lsfg.PerfMode {
+			arguments = append(arguments, "--lsfg-perf")
+		}
+		if options.Extras.Lsfg.DllPath != "" {
+			arguments = append(arguments, "--lsfg-dll-path", options.Extras.Lsfg.DllPath)
+		}
+		if options.Extras.Lsfg.Gpu != "" {
+			arguments = append(arguments, "--lsfg-gpu", options.Extras.Lsfg.Gpu)
+		}
+		if options.Extras.Lsfg.FlowScale != "" {
+			arguments = append(arguments, "--lsfg-flow-scale", options.Extras.Lsfg.FlowScale)
+		}
+		if options.Extras.Lsfg.Pacing != "" {
+			arguments = append(arguments, "--lsfg-pacing", options.Extras.Lsfg.Pacing)
+		}
+		if options.Extras.Lsfg.AllowFp16 {
+			arguments = append(arguments, "--lsfg-fp16")
+		}
+	}
+	if options.Extras.Memory.Enabled {
+		arguments = append(arguments, "--memory-min")
+		if options.Extras.Memory.Value != "" {
+			arguments = append(arguments, "--memory-min-value", options.Extras.Memory.Value)
+		}
+	}
+	protonSwitches := []struct {
+		flag    string
+		enabled bool
+	}{
+		{"--proton-no-esync", options.Extras.Proton.DisableEsync},
+		{"--proton-no-fsync", options.Extras.Proton.DisableFsync},
+		{"--proton-ntsync", options.Extras.Proton.EnableNtsync},
+		{"--proton-wined3d", options.Extras.Proton.UseWineD3D},
+		{"--proton-wayland", options.Extras.Proton.EnableWayland},
+		{"--proton-nvapi", options.Extras.Proton.EnableNvapi},
+		{"--proton-hide-nvidia-gpu", options.Extras.Proton.HideNvidiaGpu},
+		{"--proton-large-address-aware", options.Extras.Proton.LargeAddressAware},
+	}
+	for _, option := range protonSwitches {
+		if option.enabled {
+			arguments = append(arguments, option.flag)
+		}
+	}
+	if options.Extras.Scheduling.Enabled {
+		if data, err := json.Marshal(options.Extras.Scheduling); err == nil {
+			arguments = append(arguments, "--scheduling", string(data))
+		}
+	}
+	if options.Extras.Scope.Enabled {
+		if data, err := json.Marshal(options.Extras.Scope); err == nil {
+			arguments = append(arguments, "--scope", string(data))
+		}
+	}
+	if options.Extras.Gamescope.Enabled {
+		gamescope := options.Extras.Gamescope
+		arguments = append(arguments, "--gamescope",
+			"--gs-w", gamescope.Width,
+			"--gs-h", gamescope.Height,
+			"--gs-r", gamescope.RefreshRate)
+		optionalValues := []struct{ flag, value string }{
+			{"--gs-out-w", gamescope.OutputWidth},
+			{"--gs-out-h", gamescope.OutputHeight},
+			{"--gs-filter", gamescope.Filter},
+			{"--gs-scaler", gamescope.Scaler},
+			{"--gs-sharpness", gamescope.Sharpness},
+			{"--gs-fps-limit", gamescope.FrameLimit},
+		}
+		for _, option := range optionalValues {
+			if option.value != "" {
+				arguments = append(arguments, option.flag, option.value)
+			}
+		}
+		switches := []struct {
+			flag    string
+			enabled bool
+		}{
+			{"--gs-fullscreen", gamescope.Fullscreen},
+			{"--gs-borderless", gamescope.Borderless},
+			{"--gs-hdr", gamescope.HDR},
+			{"--gs-grab-cursor", gamescope.ForceGrabCursor},
+			{"--gs-adaptive-sync", gamescope.AdaptiveSync},
+			{"--gs-mangoapp", gamescope.MangoApp},
+		}
+		for _, option := range switches {
+			if option.enabled {
+				arguments = append(arguments, option.flag)
+			}
+		}
+	}
+	if !showLogs {
+		arguments = append(arguments, "--logs=false")
+	}
+	return arguments
+}
@@ -0,0 +1,172 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"light-launcher/internal/config"
+	"light-launcher/internal/system"
+	"light-launcher/internal/types"
+)
+
+// Stages of the shutdown sequence, in the order they are tried.
+const (
+	StopStageGraceful   = "graceful"
+	StopStageTerminate  = "terminate"
+	StopStageKill       = "kill"
+	StopStageWineserver = "wineserver"
+)
+
+const DefaultStopTimeout = 5 * time.Second
+
+// MaxStopTimeout bounds each stage so a whole stop request, three stages plus
+// wineserver -k, finishes within session.StopRequestTimeout.
+const MaxStopTimeout = 30 * time.Second
+
+// StopOptions configures StopProcessGroup. WinePrefix and ProtonPath are
+// used for the final wineserver -k, which reaches Wine processes that left
+// the game's process group. Wineserver, when set, is used instead of the one
+// found from ProtonPath.
+type StopOptions struct {
+	GracefulTimeout  time.Duration
+	TerminateTimeout time.Duration
+	KillTimeout      time.Duration
+	WinePrefix       string
+	ProtonPath       string
+	Wineserver       string
+}
+
+// NewStopOptions returns the stop sequence for a game, with every stage
+// waiting timeout, capped at MaxStopTimeout, before escalating.
+func NewStopOptions(options types.LaunchOptions, timeout time.Duration) StopOptions {
+	if timeout <= 0 {
+		timeout = DefaultStopTimeout
+	}
+	if timeout > MaxStopTimeout {
+		timeout = MaxStopTimeout
+	}
+	return StopOptions{
+		GracefulTimeout:  timeout,
+		TerminateTimeout: timeout,
+		KillTimeout:      timeout,
+		WinePrefix:       config.ExpandPath(options.PrefixPath),
+		ProtonPath:       config.ExpandPath(options.ProtonPath),
+	}
+}
+
+// StopProcessGroup stops the game's process group, escalating from SIGINT to
+// SIGTERM to SIGKILL until nothing in the group is left, then shuts down the
+// prefix's wineserver. The result names the stage that ended the game.
+func StopProcessGroup(process *os.Process, options StopOptions) types.StopResult {
+	var result types.StopResult
+	if process == nil {
+		result.Stopped = true
+		return result
+	}
+
+	stages := []struct {
+		name    string
+		signal  syscall.Signal
+		timeout time.Duration
+	}{
+		{StopStageGraceful, syscall.SIGINT, options.GracefulTimeout},
+		{StopStageTerminate, syscall.SIGTERM, options.TerminateTimeout},
+		{StopStageKill, syscall.SIGKILL, options.KillTimeout},
+	}
+
+	processGroupId := process.Pid
+	for _, stage := range stages {
+		if !system.IsProcessGroupAlive(processGroupId) {
+			break
+		}
+		DebugLog(fmt.Sprintf("Stopping process group %d: %s (%s)", processGroupId, stage.name, stage.signal))
+		if err := syscall.Kill(-processGroupId, stage.signal); err != nil && err != syscall.ESRCH {
+			result.Error = err.Error()
+		}
+		if WaitForProcessGroup(processGroupId, stage.timeout) {
+			result.Stage = stage.name
+			result.Stopped = true
+			break
+		}
+	}
+	if !result.Stopped && !system.IsProcessGroupAlive(processGroupId) {
+		result.Stopped = true
+	}
+	if !result.Stopped {
+		result.Error = fmt.Sprintf("process group %d survived SIGKILL", processGroupId)
+	}
+
+	killed, err := killWineserver(options)
+	if err != nil {
+		DebugLog(fmt.Sprintf("wineserver -k failed: %v", err))
+		if result.Error == "" {
+			result.Error = err.Error()
+		}
+	}
+	result.WineserverKill = killed
+	if killed && result.Stopped && result.Stage == "" {
+		result.Stage = StopStageWineserver
+	}
+	return result
+}
+
+// WaitForProcessGroup waits up to timeout for every process in the group to
+// exit and reports whether they did.
+func WaitForProcessGroup(processGroupId int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if !system.IsProcessGroupAlive(processGroupId) {
+			return true
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return !system.IsProcessGroupAlive(processGroupId)
+}
+
+// killWineserver runs wineserver -k for the prefix, preferring the wineserver
+// shipped with the session's Proton build. It reports whether a server was
+// running and got shut down.
+func killWineserver(options StopOptions) (bool, error) {
+	if options.WinePrefix == "" {
+		return false, nil
+	}
+	wineserver := options.Wineserver
+	if wineserver == "" {
+		wineserver = FindWineserver(options.ProtonPath)
+	}
+	if wineserver == "" {
+		return false, fmt.Errorf("wineserver not found")
+	}
+
+	command := exec.Command(wineserver, "-k")
+	command.Env = append(os.Environ(), "WINEPREFIX="+options.WinePrefix)
+	if err := command.Run(); err != nil {
+		// wineserver -k exits non-zero when no server was running.
+		if _, exited := err.(*exec.ExitError); exited {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// FindWineserver returns the wineserver shipped with the Proton build at
+// protonPath, falling back to the one on PATH.
+func FindWineserver(protonPath string) string {
+	if protonPath != "" {
+		for _, directory := range []string{"files", "dist"} {
+			candidate := filepath.Join(protonPath, directory, "bin", "wineserver")
+			if _, err := os.Stat(candidate); err == nil {
+				return candidate
+			}
+		}
+	}
+	if path, err := exec.LookPath("wineserver"); err == nil {
+		return path
+	}
+	return ""
+}
@@ -0,0 +1,163 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"syscall"
+
+	"light-launcher/internal/system"
+	"light-launcher/internal/types"
+)
+
+// EnvironmentVariable is set on every game an instance manager launches, to
+// its own pid. Wine processes inherit it, so it still identifies them after
+// they leave the game's process tree.
+const EnvironmentVariable = "LIGHT_LAUNCHER_SESSION"
+
+// FindCleanupProcesses lists the processes Cleanup would kill. With an empty
+// prefixPath these are the LightLauncher sessions and everything they
+// started; otherwise every process running in that WINEPREFIX and its
+// children. Only the current user's processes are considered.
+func FindCleanupProcesses(prefixPath string) []types.CleanupProcess {
+	processes := system.ListUserProcesses()
+	roots := make(map[int]string)
+
+	if prefixPath == "" {
+		for _, record := range LoadRecords() {
+			roots[record.Pid] = fmt.Sprintf("LightLauncher session %d", record.Pid)
+			if IsGameAlive(record) {
+				roots[record.GamePid] = fmt.Sprintf("game of session %d", record.Pid)
+			}
+		}
+		for _, process := range processes {
+			if _, found := roots[process.Pid]; found {
+				continue
+			}
+			if owner, found := system.ReadProcessEnvironment(process.Pid)[EnvironmentVariable]; found {
+				roots[process.Pid] = "started by LightLauncher session " + owner
+			}
+		}
+	} else {
+		prefix := filepath.Clean(prefixPath)
+		for _, process := range processes {
+			winePrefix := system.ReadProcessEnvironment(process.Pid)["WINEPREFIX"]
+			if winePrefix != "" && filepath.Clean(winePrefix) == prefix {
+				roots[process.Pid] = "running in " + prefix
+			}
+		}
+	}
+
+	children := make(map[int][]int)
+	byPid := make(map[int]system.ProcessInfo)
+	for _, process := range processes {
+		children[process.ParentPid] = append(children[process.ParentPid], process.Pid)
+		byPid[process.Pid] = process
+	}
+
+	// Never offer to kill the caller or the processes it runs under.
+	protected := make(map[int]bool)
+	for pid := os.Getpid(); pid > 1; pid = byPid[pid].ParentPid {
+		protected[pid] = true
+		if _, found := byPid[pid]; !found {
+			break
+		}
+	}
+
+	reasons := make(map[int]string)
+	queue := make([]int, 0, len(roots))
+	for pid, reason := range roots {
+		if _, alive := byPid[pid]; alive {
+			reasons[pid] = reason
+			queue = append(queue, pid)
+		}
+	}
+	for len(queue) > 0 {
+		pid := queue[0]
+		queue = queue[1:]
+		for _, child := range children[pid] {
+			if _, seen := reasons[child]; !seen {
+				reasons[child] = "child of " + strconv.Itoa(pid)
+				queue = append(queue, child)
+			}
+		}
+	}
+
+	result := make([]types.CleanupProcess, 0, len(reasons))
+	for pid, reason := range reasons {
+		if protected[pid] {
+			continue
+		}
+		process := byPid[pid]
+		result = append(result, types.CleanupProcess{
+			Pid:         pid,
+			ParentPid:   process.ParentPid,
+			StartTime:   process.StartTime,
+			Name:        process.Name,
+			CommandLine: system.ReadProcessCommandLine(pid),
+			Reason:      reason,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Pid < result[j].Pid })
+	return result
+}
+
+// Cleanup kills the processes the user confirmed from FindCleanupProcesses.
+// Instance managers are asked to stop their game over the control socket
+// first, all at once, and left to exit on their own so their post-exit work
+// still runs. A process is only killed while its pid still belongs to the
+// process that was confirmed.
+func Cleanup(processes []types.CleanupProcess) types.CleanupResult {
+	result := types.CleanupResult{
+		Processes: processes,
+		Errors:    []string{},
+	}
+
+	var mutex sync.Mutex
+	var group sync.WaitGroup
+	stopped := make(map[int]bool)
+	for _, process := range processes {
+		if !isConfirmedProcess(process) {
+			continue
+		}
+		group.Add(1)
+		go func(process types.CleanupProcess) {
+			defer group.Done()
+			_, err := Send(process.Pid, Request{Command: CommandStop})
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err == nil {
+				stopped[process.Pid] = true
+				result.Stopped++
+			} else if !errors.Is(err, ErrNoSocket) {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s (%d): %v", process.Name, process.Pid, err))
+			}
+		}(process)
+	}
+	group.Wait()
+
+	for _, process := range processes {
+		if stopped[process.Pid] || !isConfirmedProcess(process) {
+			continue
+		}
+		if err := syscall.Kill(process.Pid, syscall.SIGKILL); err != nil && err != syscall.ESRCH {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s (%d): %v", process.Name, process.Pid, err))
+			continue
+		}
+		result.Killed++
+	}
+	CleanStale()
+	return result
+}
+
+// isConfirmedProcess reports whether the process confirmed for cleanup is
+// still running under its pid.
+func isConfirmedProcess(process types.CleanupProcess) bool {
+	startTime, alive := system.GetProcessStartTime(process.Pid)
+	return alive && startTime == process.StartTime
+}
@@ -0,0 +1,73 @@
+package session
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"time"
+
+	"light-launcher/internal/executor"
+)
+
+// ErrNoSocket is returned when the instance manager has no control socket,
+// either because it exited or because it predates the socket.
+var ErrNoSocket = errors.New("session has no control socket")
+
+// StopRequestTimeout covers every stage of the shutdown sequence at its
+// longest, plus time for wineserver -k, which takes far longer than the
+// other commands.
+const StopRequestTimeout = 3*executor.MaxStopTimeout + 15*time.Second
+
+// Send delivers one request to the instance manager with pid.
+func Send(pid int, request Request) (*Response, error) {
+	path := GetSocketPath(pid)
+	if _, err := os.Stat(path); err != nil {
+		return nil, ErrNoSocket
+	}
+
+	connection, err := net.DialTimeout("unix", path, 2*time.Second)
+	if err != nil {
+		if !isManagerAlive(pid) {
+			_ = os.Remove(path)
+			return nil, ErrNoSocket
+		}
+		return nil, fmt.Errorf("failed to reach session %d: %w", pid, err)
+	}
+	defer connection.Close()
+	timeout := 5 * time.Second
+	if request.Command == CommandStop {
+		timeout = StopRequestTimeout
+	}
+	_ = connection.SetDeadline(time.Now().Add(timeout))
+
+	data, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := connection.Write(append(data, '\n')); err != nil {
+		return nil, fmt.Errorf("failed to send to session %d: %w", pid, err)
+	}
+
+	line, err := bufio.NewReader(connection).ReadBytes('\n')
+	if err != nil {
+		return nil, fmt.Errorf("no answer from session %d: %w", pid, err)
+	}
+
+	var response Response
+	if err := json.Unmarshal(line, &response); err != nil {
+		return nil, fmt.Errorf("invalid answer from session %d: %w", pid, err)
+	}
+	if response.Error != "" {
+		return &response, errors.New(response.Error)
+	}
+	return &response, nil
+}
+
+func isProcessAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,72 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"light-launcher/internal/types"
+)
+
+// Commands understood by the instance manager's control socket.
+const (
+	CommandStatus     = "status"
+	CommandStop       = "stop"
+	CommandKill       = "kill"
+	CommandToggleLogs = "toggle-logs"
+	CommandLogTail    = "log-tail"
+	CommandUsage      = "usage"
+	CommandMetrics    = "metrics"
+)
+
+// Request is sent as a single line of JSON; the instance answers with one
+// Response line and closes the connection.
+type Request struct {
+	Command string `json:"command"`
+	Lines   int    `json:"lines,omitempty"`
+}
+
+type Response struct {
+	Error       string                `json:"error,omitempty"`
+	Session     *types.RunningSession `json:"session,omitempty"`
+	Usage       *types.SessionUsage   `json:"usage,omitempty"`
+	Metrics     []types.SessionUsage  `json:"metrics,omitempty"`
+	Stop        *types.StopResult     `json:"stop,omitempty"`
+	Log         []string              `json:"log,omitempty"`
+	ShowingLogs bool                  `json:"showingLogs,omitempty"`
+}
+
+func GetSocketDirectory() string {
+	if runtimeDirectory := os.Getenv("XDG_RUNTIME_DIR"); runtimeDirectory != "" {
+		return filepath.Join(runtimeDirectory, "light-launcher")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("light-launcher-%d", os.Getuid()))
+}
+
+// GetSocketPath returns the control socket of the instance manager with pid.
+func GetSocketPath(pid int) string {
+	return filepath.Join(GetSocketDirectory(), strconv.Itoa(pid)+".sock")
+}
+
+// ListSocketPids returns the pids of every instance manager with a socket,
+// including stale ones left behind by a crash.
+func ListSocketPids() []int {
+	entries, err := os.ReadDir(GetSocketDirectory())
+	if err != nil {
+		return nil
+	}
+
+	var pids []int
+	for _, entry := range entries {
+		name, isSocket := strings.CutSuffix(entry.Name(), ".sock")
+		if !isSocket {
+			continue
+		}
+		if pid, err := strconv.Atoi(name); err == nil {
+			pids = append(pids, pid)
+		}
+	}
+	return pids
+}
@@ -0,0 +1,134 @@
+package session
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"light-launcher/internal/system"
+	"light-launcher/internal/types"
+)
+
+// The registry is one JSON record per instance manager, stored next to its
+// control socket so both disappear together.
+
+func GetRecordPath(pid int) string {
+	return filepath.Join(GetSocketDirectory(), strconv.Itoa(pid)+".json")
+}
+
+// Register writes the record for record.Pid, replacing any previous one.
+func Register(record types.RunningSession) error {
+	if err := os.MkdirAll(GetSocketDirectory(), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := GetRecordPath(record.Pid)
+	temporaryPath := path + ".tmp"
+	if err := os.WriteFile(temporaryPath, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(temporaryPath, path)
+}
+
+func Unregister(pid int) {
+	_ = os.Remove(GetRecordPath(pid))
+}
+
+// LoadRecord reads the record of the instance manager with pid, whether or
+// not it is still alive.
+func LoadRecord(pid int) (types.RunningSession, bool) {
+	var record types.RunningSession
+	data, err := os.ReadFile(GetRecordPath(pid))
+	if err != nil {
+		return record, false
+	}
+	return record, json.Unmarshal(data, &record) == nil
+}
+
+// LoadRecords returns the records of instance managers that are still alive.
+func LoadRecords() []types.RunningSession {
+	entries, err := os.ReadDir(GetSocketDirectory())
+	if err != nil {
+		return nil
+	}
+
+	var records []types.RunningSession
+	for _, entry := range entries {
+		name, isRecord := strings.CutSuffix(entry.Name(), ".json")
+		if !isRecord {
+			continue
+		}
+		pid, err := strconv.Atoi(name)
+		if err != nil {
+			continue
+		}
+		if record, found := LoadRecord(pid); found && IsSessionAlive(record) {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// IsSessionAlive reports whether the record's instance manager is still
+// running, and not some later process that reused its pid.
+func IsSessionAlive(record types.RunningSession) bool {
+	return isSameProcess(record.Pid, record.PidStartTime)
+}
+
+// IsGameAlive reports whether the game process the record names is still
+// running under its pid.
+func IsGameAlive(record types.RunningSession) bool {
+	return record.GamePid > 0 && isSameProcess(record.GamePid, record.GamePidStartTime)
+}
+
+// isManagerAlive checks the instance manager with pid against its record
+// when there is one.
+func isManagerAlive(pid int) bool {
+	if record, found := LoadRecord(pid); found {
+		return IsSessionAlive(record)
+	}
+	return isProcessAlive(pid)
+}
+
+// isSameProcess checks pid against the start time recorded for it. Records
+// written without one fall back to a plain liveness check.
+func isSameProcess(pid int, startTime uint64) bool {
+	if startTime == 0 {
+		return isProcessAlive(pid)
+	}
+	current, alive := system.GetProcessStartTime(pid)
+	return alive && current == startTime
+}
+
+// CleanStale removes records and sockets left behind by instance managers
+// that exited without cleaning up.
+func CleanStale() {
+	entries, err := os.ReadDir(GetSocketDirectory())
+	if err != nil {
+		return
+	}
+
+	// Liveness is decided before anything is removed, since it depends on
+	// the record that is about to go.
+	alive := make(map[int]bool)
+	for _, entry := range entries {
+		name := entry.Name()
+		base := strings.TrimSuffix(strings.TrimSuffix(strings.TrimSuffix(name, ".tmp"), ".json"), ".sock")
+		pid, err := strconv.Atoi(base)
+		if err != nil {
+			continue
+		}
+		if _, checked := alive[pid]; !checked {
+			alive[pid] = isManagerAlive(pid)
+		}
+		if !alive[pid] {
+			_ = os.Remove(filepath.Join(GetSocketDirectory(), name))
+		}
+	}
+}
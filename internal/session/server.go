@@ -0,0 +1,131 @@
+package session
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"light-launcher/internal/types"
+)
+
+const DefaultLogTailLines = 100
+
+// Controller is implemented by the instance manager to answer socket requests.
+type Controller interface {
+	Status() types.RunningSession
+	Stop() (types.StopResult, error)
+	Kill() error
+	ToggleLogs() (bool, error)
+	LogTail(lines int) ([]string, error)
+	Usage() (types.SessionUsage, error)
+	Metrics() []types.SessionUsage
+}
+
+type Server struct {
+	listener net.Listener
+	path     string
+}
+
+// Listen opens the control socket for the current process and serves
+// requests in the background until Close is called.
+func Listen(controller Controller) (*Server, error) {
+	if err := os.MkdirAll(GetSocketDirectory(), 0700); err != nil {
+		return nil, err
+	}
+
+	path := GetSocketPath(os.Getpid())
+	_ = os.Remove(path)
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open control socket: %w", err)
+	}
+	_ = os.Chmod(path, 0600)
+
+	server := &Server{listener: listener, path: path}
+	go server.serve(controller)
+	return server, nil
+}
+
+func (server *Server) Path() string {
+	return server.path
+}
+
+func (server *Server) Close() error {
+	err := server.listener.Close()
+	_ = os.Remove(server.path)
+	return err
+}
+
+func (server *Server) serve(controller Controller) {
+	for {
+		connection, err := server.listener.Accept()
+		if err != nil {
+			return
+		}
+		go handleConnection(connection, controller)
+	}
+}
+
+func handleConnection(connection net.Conn, controller Controller) {
+	defer connection.Close()
+	_ = connection.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var request Request
+	line, err := bufio.NewReader(connection).ReadBytes('\n')
+	if err == nil {
+		err = json.Unmarshal(line, &request)
+	}
+
+	var response Response
+	if err != nil {
+		response.Error = fmt.Sprintf("invalid request: %v", err)
+	} else {
+		response = dispatch(request, controller)
+	}
+
+	// Stop may run for a while, so the write deadline starts after dispatch.
+	data, _ := json.Marshal(response)
+	_ = connection.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	_, _ = connection.Write(append(data, '\n'))
+}
+
+func dispatch(request Request, controller Controller) Response {
+	var response Response
+	var err error
+
+	switch request.Command {
+	case CommandStatus:
+		status := controller.Status()
+		response.Session = &status
+	case CommandStop:
+		var result types.StopResult
+		result, err = controller.Stop()
+		response.Stop = &result
+	case CommandKill:
+		err = controller.Kill()
+	case CommandToggleLogs:
+		response.ShowingLogs, err = controller.ToggleLogs()
+	case CommandLogTail:
+		lines := request.Lines
+		if lines <= 0 {
+			lines = DefaultLogTailLines
+		}
+		response.Log, err = controller.LogTail(lines)
+	case CommandUsage:
+		var usage types.SessionUsage
+		usage, err = controller.Usage()
+		response.Usage = &usage
+	case CommandMetrics:
+		response.Metrics = controller.Metrics()
+	default:
+		err = fmt.Errorf("unknown command %q", request.Command)
+	}
+
+	if err != nil {
+		response.Error = err.Error()
+	}
+	return response
+}
@@ -0,0 +1,116 @@
+package system
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const cgroupRoot = "/sys/fs/cgroup"
+
+// IsSystemdUserAvailable reports whether transient user scopes can be
+// created: systemd is the init system and the user manager is running.
+func IsSystemdUserAvailable() bool {
+	if !IsCommandAvailable("systemd-run") {
+		return false
+	}
+	if _, err := os.Stat("/run/systemd/system"); err != nil {
+		return false
+	}
+	runtimeDirectory := os.Getenv("XDG_RUNTIME_DIR")
+	if runtimeDirectory == "" {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(runtimeDirectory, "systemd", "private"))
+	return err == nil
+}
+
+func IsUserUnitActive(unit string) bool {
+	return exec.Command("systemctl", "--user", "--quiet", "is-active", unit).Run() == nil
+}
+
+// GetProcessCgroup returns the cgroup v2 directory the process belongs to.
+func GetProcessCgroup(pid int) (string, error) {
+	file, err := os.Open(filepath.Join("/proc", strconv.Itoa(pid), "cgroup"))
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if path, found := strings.CutPrefix(scanner.Text(), "0::"); found {
+			return filepath.Join(cgroupRoot, path), nil
+		}
+	}
+	return "", fmt.Errorf("process %d has no cgroup v2 entry", pid)
+}
+
+// GetCgroupSample reads the totals the kernel keeps for a cgroup, which
+// include processes that left the game's process group.
+func GetCgroupSample(path string) (ProcessSample, error) {
+	var sample ProcessSample
+
+	processes, err := os.ReadFile(filepath.Join(path, "cgroup.procs"))
+	if err != nil {
+		return sample, err
+	}
+	drmClients := make(map[string]bool)
+	for _, field := range strings.Fields(string(processes)) {
+		pid, err := strconv.Atoi(field)
+		if err != nil {
+			continue
+		}
+		sample.Processes++
+		if stat, ok := readProcessStat(filepath.Join("/proc", field, "stat")); ok {
+			sample.Threads += stat.threads
+		}
+		sample.VramBytes += readProcessVram(pid, drmClients)
+	}
+
+	if io, err := os.ReadFile(filepath.Join(path, "io.stat")); err == nil {
+		sample.ReadBytes, sample.WriteBytes = parseIOStat(string(io))
+	}
+
+	if memory, err := os.ReadFile(filepath.Join(path, "memory.current")); err == nil {
+		sample.MemoryBytes, _ = strconv.ParseUint(strings.TrimSpace(string(memory)), 10, 64)
+	}
+
+	stat, err := os.ReadFile(filepath.Join(path, "cpu.stat"))
+	if err != nil {
+		return sample, err
+	}
+	for _, line := range strings.Split(string(stat), "\n") {
+		if value, found := strings.CutPrefix(line, "usage_usec "); found {
+			microseconds, _ := strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+			sample.CpuTicks = microseconds * ClockTicksPerSecond / 1000000
+			break
+		}
+	}
+	return sample, nil
+}
+
+// parseIOStat sums rbytes and wbytes over every device in a cgroup's io.stat.
+func parseIOStat(content string) (uint64, uint64) {
+	var readBytes, writeBytes uint64
+	for _, line := range strings.Split(content, "\n") {
+		for _, field := range strings.Fields(line) {
+			key, value, found := strings.Cut(field, "=")
+			if !found {
+				continue
+			}
+			number, _ := strconv.ParseUint(value, 10, 64)
+			switch key {
+			case "rbytes":
+				readBytes += number
+			case "wbytes":
+				writeBytes += number
+			}
+		}
+	}
+	return readBytes, writeBytes
+}
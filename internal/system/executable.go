@@ -0,0 +1,91 @@
+package system
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Kinds of game executables, told apart by their first bytes.
+const (
+	ExecutableWindows  = "windows"
+	ExecutableNative   = "native"
+	ExecutableAppImage = "appimage"
+	ExecutableScript   = "script"
+)
+
+// GetExecutableKind reports what kind of program path is, or "" when it is
+// not one LightLauncher can run.
+func GetExecutableKind(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	header := make([]byte, 16)
+	count, _ := file.Read(header)
+	header = header[:count]
+
+	switch {
+	case bytes.HasPrefix(header, []byte("MZ")):
+		return ExecutableWindows
+	case bytes.HasPrefix(header, []byte("\x7fELF")):
+		// AppImages are ELF files tagged "AI" plus their type at offset 8.
+		if len(header) > 10 && header[8] == 'A' && header[9] == 'I' {
+			return ExecutableAppImage
+		}
+		return ExecutableNative
+	case bytes.HasPrefix(header, []byte("#!")):
+		return ExecutableScript
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".sh") {
+		return ExecutableScript
+	}
+	return ""
+}
+
+// IsNativeExecutable reports whether path runs on Linux without Wine.
+func IsNativeExecutable(path string) bool {
+	switch GetExecutableKind(path) {
+	case ExecutableNative, ExecutableAppImage, ExecutableScript:
+		return true
+	}
+	return false
+}
+
+// IsExecutableFile reports whether path has an execute permission bit set.
+func IsExecutableFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular() && info.Mode().Perm()&0111 != 0
+}
+
+// ReadShebang returns the interpreter and optional argument named on the
+// script's #! line, or nil when path does not start with one. Like the
+// kernel, everything after the interpreter is passed as a single argument.
+func ReadShebang(path string) []string {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	header := make([]byte, 256)
+	count, _ := file.Read(header)
+	line, _, _ := bytes.Cut(header[:count], []byte("\n"))
+	if !bytes.HasPrefix(line, []byte("#!")) {
+		return nil
+	}
+
+	fields := strings.TrimSpace(string(line[2:]))
+	if fields == "" {
+		return nil
+	}
+	separator := strings.IndexAny(fields, " \t")
+	if separator < 0 {
+		return []string{fields}
+	}
+	return []string{fields[:separator], strings.TrimSpace(fields[separator+1:])}
+}
@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strings"
+
+	"light-launcher/internal/types"
 )
 
 func GetListGpus() []string {
@@ -79,3 +82,107 @@ func contains(slice []string, search string) bool {
 	}
 	return false
 }
+
+// gpuVendors names the PCI vendor IDs of GPU makers.
+var gpuVendors = map[string]string{
+	"1002": "AMD",
+	"10de": "NVIDIA",
+	"8086": "Intel",
+}
+
+// GetGpuDevices lists the GPUs in sysfs, named from vulkaninfo when it is
+// installed.
+func GetGpuDevices() []types.GpuDevice {
+	cards, err := filepath.Glob("/sys/class/drm/card[0-9]*")
+	if err != nil {
+		return nil
+	}
+
+	vulkanNames := detectVulkanDeviceNames()
+	devices := []types.GpuDevice{}
+	seen := make(map[string]bool)
+	for _, card := range cards {
+		// Connectors such as card0-DP-1 share the directory.
+		if strings.Contains(filepath.Base(card), "-") {
+			continue
+		}
+		devicePath, err := filepath.EvalSymlinks(filepath.Join(card, "device"))
+		if err != nil {
+			continue
+		}
+		address := filepath.Base(devicePath)
+		if seen[address] {
+			continue
+		}
+		seen[address] = true
+
+		device := types.GpuDevice{
+			PciAddress: address,
+			VendorID:   readHexID(filepath.Join(devicePath, "vendor")),
+			DeviceID:   readHexID(filepath.Join(devicePath, "device")),
+		}
+		device.Vendor = gpuVendors[device.VendorID]
+		if driver, err := filepath.EvalSymlinks(filepath.Join(devicePath, "driver")); err == nil {
+			device.Driver = filepath.Base(driver)
+		}
+		if bootVga, err := os.ReadFile(filepath.Join(devicePath, "boot_vga")); err == nil {
+			device.BootVga = strings.TrimSpace(string(bootVga)) == "1"
+		}
+		device.Name = vulkanNames[device.VendorID+":"+device.DeviceID]
+		if device.Name == "" {
+			device.Name = strings.TrimSpace(device.Vendor + " GPU " + device.VendorID + ":" + device.DeviceID)
+		}
+		devices = append(devices, device)
+	}
+	return devices
+}
+
+// FindGpuDevice returns the GPU at pciAddress, if it is still present.
+func FindGpuDevice(pciAddress string) (types.GpuDevice, bool) {
+	for _, device := range GetGpuDevices() {
+		if device.PciAddress == pciAddress {
+			return device, true
+		}
+	}
+	return types.GpuDevice{}, false
+}
+
+func readHexID(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(strings.TrimSpace(string(data))), "0x")
+}
+
+// detectVulkanDeviceNames maps "vendor:device" IDs to the device names
+// Vulkan reports, which are what DXVK_FILTER_DEVICE_NAME matches against.
+func detectVulkanDeviceNames() map[string]string {
+	names := make(map[string]string)
+	output, err := exec.Command("vulkaninfo", "--summary").Output()
+	if err != nil {
+		return names
+	}
+
+	var vendorID, deviceID string
+	for _, line := range strings.Split(string(output), "\n") {
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		switch key {
+		case "vendorID":
+			vendorID = strings.TrimPrefix(strings.ToLower(value), "0x")
+		case "deviceID":
+			deviceID = strings.TrimPrefix(strings.ToLower(value), "0x")
+		case "deviceName":
+			if vendorID != "" && deviceID != "" {
+				names[vendorID+":"+deviceID] = value
+			}
+			vendorID, deviceID = "", ""
+		}
+	}
+	return names
+}
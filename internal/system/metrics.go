@@ -0,0 +1,110 @@
+package system
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// readProcessIO returns the bytes the process read from and wrote to storage.
+func readProcessIO(pid int) (uint64, uint64) {
+	file, err := os.Open(filepath.Join("/proc", strconv.Itoa(pid), "io"))
+	if err != nil {
+		return 0, 0
+	}
+	defer file.Close()
+
+	var readBytes, writeBytes uint64
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), ":")
+		if !found {
+			continue
+		}
+		number, _ := strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+		switch key {
+		case "read_bytes":
+			readBytes = number
+		case "write_bytes":
+			writeBytes = number
+		}
+	}
+	return readBytes, writeBytes
+}
+
+// readProcessVram sums the video memory of the DRM clients the process holds
+// open, as reported in fdinfo by amdgpu, i915, xe and nouveau. Clients are
+// shared between processes and file descriptors, so seen tracks the ones
+// already counted. The NVIDIA driver does not report this and yields 0.
+func readProcessVram(pid int, seen map[string]bool) uint64 {
+	directory := filepath.Join("/proc", strconv.Itoa(pid), "fdinfo")
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return 0
+	}
+
+	var total uint64
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(directory, entry.Name()))
+		if err != nil || !strings.Contains(string(data), "drm-client-id") {
+			continue
+		}
+
+		var client, device string
+		var legacyVram, residentVram uint64
+		hasResident := false
+		for _, line := range strings.Split(string(data), "\n") {
+			key, value, found := strings.Cut(line, ":")
+			if !found {
+				continue
+			}
+			key = strings.TrimSpace(key)
+			switch {
+			case key == "drm-client-id":
+				client = strings.TrimSpace(value)
+			case key == "drm-pdev":
+				device = strings.TrimSpace(value)
+			case key == "drm-memory-vram":
+				legacyVram = parseKibibytes(value)
+			case strings.HasPrefix(key, "drm-resident-vram"):
+				residentVram += parseKibibytes(value)
+				hasResident = true
+			}
+		}
+
+		key := device + "/" + client
+		if client == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		if hasResident {
+			total += residentVram
+		} else {
+			total += legacyVram
+		}
+	}
+	return total
+}
+
+// parseKibibytes converts an fdinfo size such as "1024 KiB" to bytes.
+func parseKibibytes(value string) uint64 {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return 0
+	}
+	number, _ := strconv.ParseUint(fields[0], 10, 64)
+	if len(fields) < 2 {
+		return number
+	}
+	switch fields[1] {
+	case "KiB":
+		return number * 1024
+	case "MiB":
+		return number * 1024 * 1024
+	case "GiB":
+		return number * 1024 * 1024 * 1024
+	}
+	return number
+}
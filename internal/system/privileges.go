@@ -0,0 +1,55 @@
+package system
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// Capabilities from linux/capability.h that scheduling changes need.
+const (
+	CapSysAdmin = 21
+	CapSysNice  = 23
+)
+
+// rlimitNice is RLIMIT_NICE, which the syscall package does not define.
+const rlimitNice = 13
+
+// HasCapability reports whether the current process has capability in its
+// effective set.
+func HasCapability(capability uint) bool {
+	file, err := os.Open("/proc/self/status")
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		value, found := strings.CutPrefix(scanner.Text(), "CapEff:")
+		if !found {
+			continue
+		}
+		mask, err := strconv.ParseUint(strings.TrimSpace(value), 16, 64)
+		return err == nil && mask&(1<<capability) != 0
+	}
+	return false
+}
+
+// CanSetNice reports whether the current user may start a process at the
+// given nice level. Raising priority below zero needs CAP_SYS_NICE or an
+// RLIMIT_NICE that allows it.
+func CanSetNice(nice int) bool {
+	if nice >= 0 || HasCapability(CapSysNice) {
+		return true
+	}
+	var limit syscall.Rlimit
+	if err := syscall.Getrlimit(rlimitNice, &limit); err != nil {
+		return false
+	}
+	// RLIMIT_NICE stores the lowest allowed nice level as 20 - limit, so
+	// anything from 40 up allows every level.
+	return limit.Cur >= 40 || 20-int(limit.Cur) <= nice
+}
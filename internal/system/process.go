@@ -0,0 +1,197 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// ClockTicksPerSecond is USER_HZ, the unit of the CPU times in /proc/<pid>/stat.
+const ClockTicksPerSecond = 100
+
+// ProcessSample is a point-in-time reading of a process group. CpuTicks and
+// the IO byte counts are cumulative.
+type ProcessSample struct {
+	Processes   int
+	Threads     int
+	CpuTicks    uint64
+	MemoryBytes uint64
+	VramBytes   uint64
+	ReadBytes   uint64
+	WriteBytes  uint64
+}
+
+// ProcessInfo identifies one process in /proc. StartTime is in clock ticks
+// since boot and tells a process apart from a later one reusing its pid.
+type ProcessInfo struct {
+	Pid          int
+	ParentPid    int
+	ProcessGroup int
+	Name         string
+	StartTime    uint64
+}
+
+type processStat struct {
+	pid           int
+	parentPid     int
+	name          string
+	state         byte
+	processGroup  int
+	threads       int
+	cpuTicks      uint64
+	startTime     uint64
+	residentPages uint64
+}
+
+// GetProcessGroupSample sums the usage of every process whose process group
+// is processGroupId. IO of processes that already exited is not included.
+func GetProcessGroupSample(processGroupId int) ProcessSample {
+	var sample ProcessSample
+	pageSize := uint64(os.Getpagesize())
+	drmClients := make(map[string]bool)
+	for _, stat := range readProcessStats() {
+		if stat.processGroup != processGroupId {
+			continue
+		}
+		sample.Processes++
+		sample.Threads += stat.threads
+		sample.CpuTicks += stat.cpuTicks
+		sample.MemoryBytes += stat.residentPages * pageSize
+
+		readBytes, writeBytes := readProcessIO(stat.pid)
+		sample.ReadBytes += readBytes
+		sample.WriteBytes += writeBytes
+		sample.VramBytes += readProcessVram(stat.pid, drmClients)
+	}
+	return sample
+}
+
+// IsProcessGroupAlive reports whether any process in the group is still
+// running. Zombies waiting to be reaped do not count.
+func IsProcessGroupAlive(processGroupId int) bool {
+	for _, stat := range readProcessStats() {
+		if stat.processGroup == processGroupId && stat.state != 'Z' {
+			return true
+		}
+	}
+	return false
+}
+
+// ListUserProcesses returns the live processes owned by the current user.
+func ListUserProcesses() []ProcessInfo {
+	uid := uint32(os.Getuid())
+	var processes []ProcessInfo
+	for _, stat := range readProcessStats() {
+		if stat.state == 'Z' {
+			continue
+		}
+		info, err := os.Stat(filepath.Join("/proc", strconv.Itoa(stat.pid)))
+		if err != nil {
+			continue
+		}
+		if owner, ok := info.Sys().(*syscall.Stat_t); !ok || owner.Uid != uid {
+			continue
+		}
+		processes = append(processes, ProcessInfo{
+			Pid:          stat.pid,
+			ParentPid:    stat.parentPid,
+			ProcessGroup: stat.processGroup,
+			Name:         stat.name,
+			StartTime:    stat.startTime,
+		})
+	}
+	return processes
+}
+
+// ReadProcessEnvironment returns the environment a process was started with.
+func ReadProcessEnvironment(pid int) map[string]string {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "environ"))
+	if err != nil {
+		return nil
+	}
+
+	environment := make(map[string]string)
+	for _, entry := range strings.Split(string(data), "\x00") {
+		if key, value, found := strings.Cut(entry, "="); found {
+			environment[key] = value
+		}
+	}
+	return environment
+}
+
+func ReadProcessCommandLine(pid int) string {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "cmdline"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(strings.ReplaceAll(string(data), "\x00", " "))
+}
+
+// GetProcessStartTime returns when pid started, in clock ticks since boot,
+// and false when no such process is running.
+func GetProcessStartTime(pid int) (uint64, bool) {
+	stat, ok := readProcessStat(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+	if !ok || stat.state == 'Z' {
+		return 0, false
+	}
+	return stat.startTime, true
+}
+
+func readProcessStats() []processStat {
+	entries, err := filepath.Glob("/proc/[0-9]*/stat")
+	if err != nil {
+		return nil
+	}
+
+	var stats []processStat
+	for _, entry := range entries {
+		if stat, ok := readProcessStat(entry); ok {
+			stats = append(stats, stat)
+		}
+	}
+	return stats
+}
+
+func readProcessStat(path string) (processStat, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return processStat{}, false
+	}
+
+	// The command name may contain spaces, so fields are counted from the
+	// closing parenthesis; fields[0] is the state, the third field of stat.
+	content := string(data)
+	closing := strings.LastIndexByte(content, ')')
+	if closing < 0 {
+		return processStat{}, false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(content[:strings.IndexByte(content, '(')]))
+	if err != nil {
+		return processStat{}, false
+	}
+	fields := strings.Fields(content[closing+1:])
+	if len(fields) < 22 {
+		return processStat{}, false
+	}
+
+	parentPid, _ := strconv.Atoi(fields[1])
+	processGroup, _ := strconv.Atoi(fields[2])
+	threads, _ := strconv.Atoi(fields[17])
+	userTicks, _ := strconv.ParseUint(fields[11], 10, 64)
+	systemTicks, _ := strconv.ParseUint(fields[12], 10, 64)
+	startTime, _ := strconv.ParseUint(fields[19], 10, 64)
+	residentPages, _ := strconv.ParseUint(fields[21], 10, 64)
+	return processStat{
+		pid:           pid,
+		parentPid:     parentPid,
+		name:          content[strings.IndexByte(content, '(')+1 : closing],
+		state:         fields[0][0],
+		processGroup:  processGroup,
+		threads:       threads,
+		cpuTicks:      userTicks + systemTicks,
+		startTime:     startTime,
+		residentPages: residentPages,
+	}, true
+}
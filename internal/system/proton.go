@@ -7,7 +7,10 @@ import (
 	"os"
 	"os/user"
 	"path/filepath"
+	"regexp"
 	"sort"
+	"strconv"
+	"strings"
 )
 
 func GetProtonTools() ([]types.ProtonTool, error) {
@@ -16,18 +19,22 @@ func GetProtonTools() ([]types.ProtonTool, error) {
 		return nil, fmt.Errorf("failed to get current user: %w", err)
 	}
 
-	searchPaths := []struct {
+	type searchPath struct {
 		path    string
 		isSteam bool
-	}{
-		{filepath.Join(currentUser.HomeDir, ".steam/root/compatibilitytools.d"), false},
-		{filepath.Join(currentUser.HomeDir, ".local/share/Steam/compatibilitytools.d"), false},
-		{"/usr/share/steam/compatibilitytools.d", false},
-		{filepath.Join(currentUser.HomeDir, ".steam/root/steamapps/common"), true},
-		{filepath.Join(currentUser.HomeDir, ".local/share/Steam/steamapps/common"), true},
-		{filepath.Join(currentUser.HomeDir, "LightLauncher/protons"), false},
 	}
 
+	steamRoots := steamRootCandidates(currentUser.HomeDir)
+	var searchPaths []searchPath
+	for _, steamRoot := range steamRoots {
+		searchPaths = append(searchPaths, searchPath{filepath.Join(steamRoot, "compatibilitytools.d"), false})
+	}
+	searchPaths = append(searchPaths, searchPath{"/usr/share/steam/compatibilitytools.d", false})
+	for _, steamRoot := range steamRoots {
+		searchPaths = append(searchPaths, searchPath{filepath.Join(steamRoot, "steamapps/common"), true})
+	}
+	searchPaths = append(searchPaths, searchPath{filepath.Join(currentUser.HomeDir, "LightLauncher/protons"), false})
+
 	var tools []types.ProtonTool
 	seenPaths := make(map[string]bool)
 
@@ -89,3 +96,56 @@ func scanProtonDirectory(path string, isSteam bool) ([]types.ProtonTool, error)
 	}
 	return tools, nil
 }
+
+// Proton build flavors ReadProtonBuild recognises.
+const (
+	ProtonFlavorValve        = "valve"
+	ProtonFlavorExperimental = "experimental"
+	ProtonFlavorGE           = "ge"
+	ProtonFlavorCachyOS      = "cachyos"
+)
+
+// ProtonBuild identifies a Proton build from its version file, which holds a
+// timestamp and a name such as "GE-Proton9-20" or "proton-9.0-4".
+type ProtonBuild struct {
+	Name   string
+	Flavor string
+	Major  int
+	Minor  int
+}
+
+var protonBuildPatterns = []struct {
+	flavor  string
+	pattern *regexp.Regexp
+}{
+	{ProtonFlavorGE, regexp.MustCompile(`(?i)^GE-Proton(\d+)-(\d+)`)},
+	{ProtonFlavorCachyOS, regexp.MustCompile(`(?i)^proton-cachyos-(\d+)\.(\d+)`)},
+	{ProtonFlavorExperimental, regexp.MustCompile(`(?i)^experimental-(\d+)\.(\d+)`)},
+	{ProtonFlavorValve, regexp.MustCompile(`(?i)^(?:UMU-)?proton[- ](\d+)\.(\d+)`)},
+}
+
+// ReadProtonBuild reads the build of the Proton at protonPath, falling back
+// to the directory name when it has no version file. The second result is
+// false when the build is not one of the known flavors.
+func ReadProtonBuild(protonPath string) (ProtonBuild, bool) {
+	name := filepath.Base(protonPath)
+	if data, err := os.ReadFile(filepath.Join(protonPath, "version")); err == nil {
+		fields := strings.Fields(string(data))
+		if len(fields) > 0 {
+			name = fields[len(fields)-1]
+		}
+	}
+
+	build := ProtonBuild{Name: name}
+	for _, candidate := range protonBuildPatterns {
+		matches := candidate.pattern.FindStringSubmatch(name)
+		if matches == nil {
+			continue
+		}
+		build.Flavor = candidate.flavor
+		build.Major, _ = strconv.Atoi(matches[1])
+		build.Minor, _ = strconv.Atoi(matches[2])
+		return build, true
+	}
+	return build, false
+}
@@ -4,8 +4,63 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 )
 
+func steamRootCandidates(homeDirectory string) []string {
+	return []string{
+		filepath.Join(homeDirectory, ".steam/root"),
+		filepath.Join(homeDirectory, ".local/share/Steam"),
+		filepath.Join(homeDirectory, ".var/app/com.valvesoftware.Steam/.local/share/Steam"),
+	}
+}
+
+// GetSteamRoots returns the Steam installations that exist, with symlinks
+// such as ~/.steam/root resolved so each install is listed once.
+func GetSteamRoots() []string {
+	homeDirectory, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+
+	var roots []string
+	seen := make(map[string]bool)
+	for _, candidate := range steamRootCandidates(homeDirectory) {
+		realPath, err := filepath.EvalSymlinks(candidate)
+		if err != nil {
+			continue
+		}
+		if info, err := os.Stat(realPath); err != nil || !info.IsDir() || seen[realPath] {
+			continue
+		}
+		seen[realPath] = true
+		roots = append(roots, realPath)
+	}
+	return roots
+}
+
+// GetSteamUserConfigDirectories returns userdata/<id>/config for every Steam
+// account that has logged in on this machine.
+func GetSteamUserConfigDirectories() []string {
+	var directories []string
+	for _, root := range GetSteamRoots() {
+		entries, err := os.ReadDir(filepath.Join(root, "userdata"))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			if id, err := strconv.ParseUint(entry.Name(), 10, 64); err != nil || id == 0 {
+				continue
+			}
+			directories = append(directories, filepath.Join(root, "userdata", entry.Name(), "config"))
+		}
+	}
+	return directories
+}
+
 func GetShaderCacheSize() string {
 	homeDirectory, _ := os.UserHomeDir()
 	paths := []string{
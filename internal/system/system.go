@@ -79,6 +79,8 @@ func GetSystemToolsStatus() types.SystemToolsStatus {
 		HasMangoHud:   IsCommandAvailable("mangohud"),
 		HasGameMode:    IsCommandAvailable("gamemoderun"),
 		HasVulkanInfo: IsCommandAvailable("vulkaninfo"),
+		HasUmu:        IsCommandAvailable("umu-run"),
+		HasWine:       IsCommandAvailable("wine"),
 	}
 }
 
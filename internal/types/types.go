@@ -12,10 +12,22 @@ type LsfgConfig struct {
 }
 
 type GamescopeConfig struct {
-	Enabled     bool   `json:"Enabled"`
-	Width       string `json:"Width"`
-	Height      string `json:"Height"`
-	RefreshRate string `json:"RefreshRate"`
+	Enabled         bool   `json:"Enabled"`
+	Width           string `json:"Width"`
+	Height          string `json:"Height"`
+	RefreshRate     string `json:"RefreshRate"`
+	OutputWidth     string `json:"OutputWidth"`
+	OutputHeight    string `json:"OutputHeight"`
+	Fullscreen      bool   `json:"Fullscreen"`
+	Borderless      bool   `json:"Borderless"`
+	Filter          string `json:"Filter"`
+	Scaler          string `json:"Scaler"`
+	Sharpness       string `json:"Sharpness"`
+	FrameLimit      string `json:"FrameLimit"`
+	HDR             bool   `json:"HDR"`
+	ForceGrabCursor bool   `json:"ForceGrabCursor"`
+	AdaptiveSync    bool   `json:"AdaptiveSync"`
+	MangoApp        bool   `json:"MangoApp"`
 }
 
 type MemoryConfig struct {
@@ -23,12 +35,113 @@ type MemoryConfig struct {
 	Value   string `json:"Value"`
 }
 
+// ScopeConfig limits the game's resources through its transient systemd
+// scope. Values use systemd's own syntax, e.g. "8G", "150%" or "0-3".
+type ScopeConfig struct {
+	Enabled     bool   `json:"Enabled"`
+	MemoryMax   string `json:"MemoryMax"`
+	MemoryHigh  string `json:"MemoryHigh"`
+	CPUQuota    string `json:"CPUQuota"`
+	CPUWeight   string `json:"CPUWeight"`
+	IOWeight    string `json:"IOWeight"`
+	AllowedCPUs string `json:"AllowedCPUs"`
+}
+
+// SchedulingConfig sets the CPU affinity and priorities the game runs with.
+// IdlePriority runs it under SCHED_IDLE, meant for launchers and updaters
+// left running in the background.
+type SchedulingConfig struct {
+	Enabled      bool   `json:"Enabled"`
+	CpuSet       string `json:"CpuSet"`
+	Nice         string `json:"Nice"`
+	IoniceClass  string `json:"IoniceClass"`
+	IoniceLevel  string `json:"IoniceLevel"`
+	IdlePriority bool   `json:"IdlePriority"`
+}
+
+// ProtonOptions are the Proton runtime switches. False leaves Proton's own
+// default in place.
+type ProtonOptions struct {
+	DisableEsync      bool `json:"DisableEsync"`
+	DisableFsync      bool `json:"DisableFsync"`
+	EnableNtsync      bool `json:"EnableNtsync"`
+	UseWineD3D        bool `json:"UseWineD3D"`
+	EnableWayland     bool `json:"EnableWayland"`
+	EnableNvapi       bool `json:"EnableNvapi"`
+	HideNvidiaGpu     bool `json:"HideNvidiaGpu"`
+	LargeAddressAware bool `json:"LargeAddressAware"`
+}
+
+type MangoHudConfig struct {
+	PerGame       bool     `json:"PerGame"`
+	Preset        string   `json:"Preset"`
+	Position      string   `json:"Position"`
+	FpsLimit      string   `json:"FpsLimit"`
+	ShowFps       bool     `json:"ShowFps"`
+	ShowFrametime bool     `json:"ShowFrametime"`
+	ShowCpu       bool     `json:"ShowCpu"`
+	ShowGpu       bool     `json:"ShowGpu"`
+	ShowCpuTemp   bool     `json:"ShowCpuTemp"`
+	ShowGpuTemp   bool     `json:"ShowGpuTemp"`
+	ShowRam       bool     `json:"ShowRam"`
+	ShowVram      bool     `json:"ShowVram"`
+	ShowBattery   bool     `json:"ShowBattery"`
+	LogFolder     string   `json:"LogFolder"`
+	ExtraLines    []string `json:"ExtraLines"`
+	// ChangedToggles names the MangoHud parameters of the switches the user
+	// set, e.g. "fps". Only these are written, so the rest follow the preset.
+	ChangedToggles []string `json:"ChangedToggles"`
+}
+
+// GpuDevice is a display adapter found in sysfs. PciAddress identifies it
+// across reboots; VendorID and DeviceID are lowercase hex without 0x.
+type GpuDevice struct {
+	PciAddress string `json:"pciAddress"`
+	VendorID   string `json:"vendorId"`
+	DeviceID   string `json:"deviceId"`
+	Vendor     string `json:"vendor"`
+	Driver     string `json:"driver"`
+	Name       string `json:"name"`
+	BootVga    bool   `json:"bootVga"`
+}
+
+// UmuEntry is one row of the umu-database. Codename is the game's ID in its
+// store, e.g. the Steam app ID or the Epic codename.
+type UmuEntry struct {
+	Title    string   `json:"title"`
+	Store    string   `json:"store"`
+	Codename string   `json:"codename"`
+	UmuID    string   `json:"umuId"`
+	Acronym  string   `json:"acronym"`
+	Notes    string   `json:"notes"`
+	ExeNames []string `json:"exeNames"`
+}
+
 type ExtrasConfig struct {
-	EnableMangoHud bool            `json:"EnableMangoHud"`
-	EnableGamemode bool            `json:"EnableGamemode"`
-	Lsfg           LsfgConfig      `json:"Lsfg"`
-	Gamescope      GamescopeConfig `json:"Gamescope"`
-	Memory         MemoryConfig    `json:"Memory"`
+	EnableMangoHud bool             `json:"EnableMangoHud"`
+	EnableGamemode bool             `json:"EnableGamemode"`
+	MangoHud       MangoHudConfig   `json:"MangoHud"`
+	Lsfg           LsfgConfig       `json:"Lsfg"`
+	Gamescope      GamescopeConfig  `json:"Gamescope"`
+	Memory         MemoryConfig     `json:"Memory"`
+	Scope          ScopeConfig      `json:"Scope"`
+	Scheduling     SchedulingConfig `json:"Scheduling"`
+	Proton         ProtonOptions    `json:"Proton"`
+	// GpuDevice is the PCI address of the GPU to render on, empty for the
+	// system default.
+	GpuDevice string `json:"GpuDevice"`
+}
+
+type EnvironmentVariable struct {
+	Key   string `json:"Key"`
+	Value string `json:"Value"`
+	Unset bool   `json:"Unset"`
+}
+
+type HookCommand struct {
+	Command        string `json:"Command"`
+	TimeoutSeconds int    `json:"TimeoutSeconds"`
+	AbortOnFailure bool   `json:"AbortOnFailure"`
 }
 
 type LaunchOptions struct {
@@ -39,8 +152,18 @@ type LaunchOptions struct {
 	UseGamePath   bool         `json:"UseGamePath"`
 	PrefixPath    string       `json:"PrefixPath"`
 	ProtonPath    string       `json:"ProtonPath"`
+	Backend       string       `json:"Backend"`
+	WinePath      string       `json:"WinePath"`
 	CustomArgs    string       `json:"CustomArgs"`
+	UmuGameID     string       `json:"UmuGameID"`
+	UmuStore      string       `json:"UmuStore"`
+	Environment   []EnvironmentVariable `json:"Environment"`
+	PreLaunchHooks []HookCommand        `json:"PreLaunchHooks"`
+	PostExitHooks  []HookCommand        `json:"PostExitHooks"`
 	Extras        ExtrasConfig `json:"Extras"`
+	// ResolvedGpu is Extras.GpuDevice looked up for one launch, so it is
+	// only searched for once. It is never saved.
+	ResolvedGpu *GpuDevice `json:"-"`
 }
 
 type SystemToolsStatus struct {
@@ -48,6 +171,8 @@ type SystemToolsStatus struct {
 	HasMangoHud   bool `json:"hasMangoHud"`
 	HasGameMode    bool `json:"hasGameMode"`
 	HasVulkanInfo bool `json:"hasVulkanInfo"`
+	HasUmu        bool `json:"hasUmu"`
+	HasWine       bool `json:"hasWine"`
 }
 
 type SystemInfo struct {
@@ -77,17 +202,92 @@ type LsfgProfileData struct {
 }
 
 type GameInfo struct {
-	Name     string        `json:"name"`
-	Path     string        `json:"path"`
-	Icon     string        `json:"icon"`
-	Config   LaunchOptions `json:"config"`
-	IsRecent bool          `json:"isRecent"`
+	Name          string        `json:"name"`
+	Path          string        `json:"path"`
+	Icon          string        `json:"icon"`
+	Config        LaunchOptions `json:"config"`
+	IsRecent      bool          `json:"isRecent"`
+	LastPlayed    int64         `json:"lastPlayed"`
+	TotalPlaytime int64         `json:"totalPlaytime"`
+}
+
+// PlaySession is one finished launch recorded in a game's history.
+type PlaySession struct {
+	StartedAt       int64  `json:"startedAt"`
+	EndedAt         int64  `json:"endedAt"`
+	DurationSeconds int64  `json:"durationSeconds"`
+	ExitCode        int    `json:"exitCode"`
+	Crashed         bool   `json:"crashed"`
+	ProtonVersion   string `json:"protonVersion"`
+}
+
+type CrashFinding struct {
+	Rule    string   `json:"rule"`
+	Title   string   `json:"title"`
+	Hint    string   `json:"hint"`
+	Fatal   bool     `json:"fatal"`
+	Details []string `json:"details"`
+	Line    string   `json:"line"`
+}
+
+// CrashReport explains why a session ended abnormally.
+type CrashReport struct {
+	GameID    string         `json:"gameId"`
+	GameName  string         `json:"gameName"`
+	CreatedAt int64          `json:"createdAt"`
+	ExitCode  int            `json:"exitCode"`
+	Signal    string         `json:"signal"`
+	Crashed   bool           `json:"crashed"`
+	Summary   string         `json:"summary"`
+	LogPath   string         `json:"logPath"`
+	Findings  []CrashFinding `json:"findings"`
+}
+
+type PlaytimeSummary struct {
+	TotalSeconds int64 `json:"totalSeconds"`
+	LastPlayed   int64 `json:"lastPlayed"`
+	SessionCount int   `json:"sessionCount"`
 }
 
+// RunningSession is the registry record of an instance manager. The start
+// times, in clock ticks since boot, tell its processes apart from later ones
+// that reuse the pids. WinePrefix and Wineserver are what the launch backend
+// resolved for wineserver -k, and are empty for native games.
 type RunningSession struct {
-	Pid      int    `json:"pid"`
-	GamePath string `json:"gamePath"`
-	GameName string `json:"gameName"`
+	Pid              int      `json:"pid"`
+	PidStartTime     uint64   `json:"pidStartTime"`
+	GamePid          int      `json:"gamePid"`
+	GamePidStartTime uint64   `json:"gamePidStartTime"`
+	GameID           string   `json:"gameId"`
+	GamePath         string   `json:"gamePath"`
+	GameName         string   `json:"gameName"`
+	ProtonPath       string   `json:"protonPath"`
+	ProtonVersion    string   `json:"protonVersion"`
+	PrefixPath       string   `json:"prefixPath"`
+	WinePrefix       string   `json:"winePrefix"`
+	Wineserver       string   `json:"wineserver"`
+	Wrappers         []string `json:"wrappers"`
+	ScopeUnit        string   `json:"scopeUnit"`
+	LogPath          string   `json:"logPath"`
+	StartedAt        int64    `json:"startedAt"`
+	Running          bool     `json:"running"`
+	ShowingLogs      bool     `json:"showingLogs"`
+}
+
+// SessionUsage is read from the session's cgroup when it runs in its own
+// scope, and summed over its process group otherwise.
+type SessionUsage struct {
+	Timestamp        int64   `json:"timestamp"`
+	Processes        int     `json:"processes"`
+	Threads          int     `json:"threads"`
+	CpuPercent       float64 `json:"cpuPercent"`
+	MemoryBytes      uint64  `json:"memoryBytes"`
+	VramBytes        uint64  `json:"vramBytes"`
+	ReadBytes        uint64  `json:"readBytes"`
+	WriteBytes       uint64  `json:"writeBytes"`
+	ReadBytesPerSec  float64 `json:"readBytesPerSec"`
+	WriteBytesPerSec float64 `json:"writeBytesPerSec"`
+	Source           string  `json:"source"`
 }
 
 type UtilsStatus struct {
@@ -104,4 +304,35 @@ type ProtonTool struct {
 
 type AppSettings struct {
 	TransparentMode bool `json:"TransparentMode"`
+	// StopTimeoutSeconds is how long each shutdown stage waits for the game
+	// to exit before escalating. Zero uses the default.
+	StopTimeoutSeconds int `json:"StopTimeoutSeconds"`
+}
+
+// CleanupProcess is a process FindCleanupProcesses offers to kill. StartTime
+// lets CleanupProcesses skip a pid that was reused after the preview.
+type CleanupProcess struct {
+	Pid         int    `json:"pid"`
+	ParentPid   int    `json:"parentPid"`
+	StartTime   uint64 `json:"startTime"`
+	Name        string `json:"name"`
+	CommandLine string `json:"commandLine"`
+	Reason      string `json:"reason"`
+}
+
+// CleanupResult counts the instance managers that stopped their game over the
+// control socket separately from the processes that were killed.
+type CleanupResult struct {
+	Processes []CleanupProcess `json:"processes"`
+	Stopped   int              `json:"stopped"`
+	Killed    int              `json:"killed"`
+	Errors    []string         `json:"errors"`
+}
+
+// StopResult reports how far the shutdown sequence had to escalate.
+type StopResult struct {
+	Stage          string `json:"stage"`
+	Stopped        bool   `json:"stopped"`
+	WineserverKill bool   `json:"wineserverKill"`
+	Error          string `json:"error,omitempty"`
 }
@@ -33,6 +33,25 @@ func GetVersion() string {
 	return "installed"
 }
 
+// GetDisableEnvironment returns the variables the layer manifest declares for
+// turning the implicit layer off.
+func GetDisableEnvironment() map[string]string {
+	data, err := os.ReadFile(ManifestPath)
+	if err != nil {
+		return nil
+	}
+
+	var manifest struct {
+		Layer struct {
+			DisableEnvironment map[string]string `json:"disable_environment"`
+		} `json:"layer"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil
+	}
+	return manifest.Layer.DisableEnvironment
+}
+
 func GetStatus() Status {
 	return Status{
 		IsInstalled: IsInstalled(),
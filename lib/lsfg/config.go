@@ -8,8 +8,6 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
-
-	"github.com/pelletier/go-toml/v2"
 )
 
 func GetConfigPath() (string, error) {
@@ -39,9 +37,13 @@ func FindProfileForGameAtPath(gamePath, configPath string) (*ConfigProfile, int,
 		return nil, -1, fmt.Errorf("failed to read LSFG config: %w", err)
 	}
 
-	var config ConfigFile
-	if err := toml.Unmarshal(data, &config); err != nil {
-		return nil, -1, fmt.Errorf("failed to parse LSFG config: %w", err)
+	document, err := ParseDocument(data)
+	if err != nil {
+		return nil, -1, err
+	}
+	config, err := document.Decode()
+	if err != nil {
+		return nil, -1, err
 	}
 
 	executableName := strings.ToLower(filepath.Base(gamePath))
@@ -88,69 +90,148 @@ func matchesProfile(executableName string, activeIn interface{}) bool {
 	return false
 }
 
-func SaveProfileToPath(profileName, gamePath, configPath string, multiplier int, performanceMode bool, dllPath, gpu, flowScale, pacing string, allowFp16 bool) error {
-	configDirectory := filepath.Dir(configPath)
-	if err := os.MkdirAll(configDirectory, 0755); err != nil {
-		return err
+// ReadConfigFile decodes the lsfg-vk config at configPath in either format.
+func ReadConfigFile(configPath string) (*ConfigFile, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read LSFG config: %w", err)
 	}
+	document, err := ParseDocument(data)
+	if err != nil {
+		return nil, err
+	}
+	return document.Decode()
+}
+
+// profileLayout names the table holding per-game settings and the key that
+// selects executables for a config format version.
+func profileLayout(version int) (table string, matchKey string) {
+	if version == 1 {
+		return "game", "exe"
+	}
+	return "profile", "active_in"
+}
 
-	var config ConfigFile
-	if data, err := os.ReadFile(configPath); err == nil {
-		if err := toml.Unmarshal(data, &config); err != nil {
-			return fmt.Errorf("failed to parse existing LSFG config: %w", err)
+// findProfile returns the position of the profile active in executableName,
+// falling back to one named profileName when that is not empty.
+func findProfile(document *Document, executableName, profileName string) (int, bool) {
+	tableName, matchKey := profileLayout(document.Version())
+	count := document.countTables(tableName, true)
+
+	for index := 0; index < count; index++ {
+		table, _ := document.tableAt(tableName, true, index)
+		if activeIn, found := document.getValue(table, matchKey); found && matchesProfile(executableName, activeIn) {
+			return index, true
 		}
-	} else {
-		config = ConfigFile{
-			Version: 2,
-			Global: GlobalConfig{
-				Version:   2,
-				AllowFP16: allowFp16,
-				DLL:       dllPath,
-			},
-			Profiles: []ConfigProfile{},
+	}
+	if profileName == "" || tableName != "profile" {
+		return -1, false
+	}
+	for index := 0; index < count; index++ {
+		table, _ := document.tableAt(tableName, true, index)
+		if name, found := document.getValue(table, "name"); found {
+			if value, ok := name.(string); ok && strings.EqualFold(value, profileName) {
+				return index, true
+			}
 		}
 	}
+	return -1, false
+}
+
+// addActiveIn returns activeIn with executableName added, keeping any other
+// executables the profile is already active in.
+func addActiveIn(activeIn interface{}, executableName string) interface{} {
+	switch value := activeIn.(type) {
+	case string:
+		if value == "" || strings.EqualFold(value, executableName) {
+			return executableName
+		}
+		return []interface{}{value, executableName}
+	case []interface{}:
+		if matchesProfile(executableName, value) {
+			return value
+		}
+		return append(value, executableName)
+	}
+	return executableName
+}
+
+// removeActiveIn returns a single-string activeIn without executableName.
+func removeActiveIn(activeIn interface{}, executableName string) interface{} {
+	if value, ok := activeIn.(string); ok && strings.EqualFold(value, executableName) {
+		return ""
+	}
+	return activeIn
+}
 
-	config.Version = 2
-	config.Global.Version = 2
-	config.Global.DLL = dllPath
-	config.Global.AllowFP16 = allowFp16
+// SaveProfileToPath updates or adds the profile for gamePath in place, leaving
+// comments, unknown keys and other profiles as they were.
+func SaveProfileToPath(profileName, gamePath, configPath string, multiplier int, performanceMode bool, dllPath, gpu, flowScale, pacing string, allowFp16 bool) error {
+	document, err := LoadDocument(configPath)
+	if err != nil {
+		return err
+	}
 
+	version := document.Version()
 	executableName := filepath.Base(gamePath)
-	found := false
-	for index, profile := range config.Profiles {
-		if matchesProfile(strings.ToLower(executableName), profile.ActiveIn) || strings.EqualFold(profile.Name, profileName) {
-			config.Profiles[index].Name = profileName
-			config.Profiles[index].ActiveIn = executableName
-			config.Profiles[index].Multiplier = multiplier
-			config.Profiles[index].PerformanceMode = performanceMode
-			config.Profiles[index].GPU = gpu
-			config.Profiles[index].FlowScale = parseFlowScale(flowScale)
-			config.Profiles[index].Pacing = pacing
-			found = true
-			break
-		}
+
+	globalKeys := []string{"dll", "allow_fp16"}
+	globalValues := map[string]interface{}{"dll": dllPath, "allow_fp16": allowFp16}
+	if version == 1 {
+		globalKeys = []string{"dll", "no_fp16"}
+		globalValues = map[string]interface{}{"dll": dllPath, "no_fp16": !allowFp16}
+	}
+	if _, found := document.tableAt("global", false, 0); found {
+		err = document.setValues("global", false, 0, globalKeys, globalValues)
+	} else {
+		err = document.appendTable("global", false, globalKeys, globalValues)
+	}
+	if err != nil {
+		return err
 	}
 
+	tableName, matchKey := profileLayout(version)
+	keys := []string{"name", "active_in", "multiplier", "performance_mode", "gpu", "flow_scale", "pacing"}
+	values := map[string]interface{}{
+		"name":             profileName,
+		"active_in":        executableName,
+		"multiplier":       multiplier,
+		"performance_mode": performanceMode,
+		"gpu":              gpu,
+		"flow_scale":       parseFlowScale(flowScale),
+		"pacing":           pacing,
+	}
+	if version == 1 {
+		keys = []string{"exe", "multiplier", "flow_scale", "performance_mode"}
+		values["exe"] = executableName
+	}
+
+	index, found := findProfile(document, executableName, profileName)
 	if !found {
-		newProfile := ConfigProfile{
-			Name:            profileName,
-			ActiveIn:        executableName,
-			Multiplier:      multiplier,
-			PerformanceMode: performanceMode,
-			GPU:             gpu,
-			FlowScale:       parseFlowScale(flowScale),
-			Pacing:          pacing,
+		if err := document.appendTable(tableName, true, keys, values); err != nil {
+			return err
 		}
-		config.Profiles = append(config.Profiles, newProfile)
+		return document.Save(configPath)
 	}
 
-	data, err := toml.Marshal(config)
-	if err != nil {
-		return fmt.Errorf("failed to marshal LSFG config: %w", err)
+	table, _ := document.tableAt(tableName, true, index)
+	current, _ := document.getValue(table, matchKey)
+	values[matchKey] = current
+	if version != 1 {
+		if list, isList := current.([]interface{}); isList && !matchesProfile(executableName, list) {
+			if err := document.appendToArray(table, matchKey, executableName); err != nil {
+				return err
+			}
+			current, _ = document.getValue(table, matchKey)
+			values[matchKey] = current
+		} else {
+			values[matchKey] = addActiveIn(current, executableName)
+		}
 	}
-
-	return os.WriteFile(configPath, data, 0644)
+	if err := document.setValues(tableName, true, index, keys, values); err != nil {
+		return err
+	}
+	return document.Save(configPath)
 }
 
 func SaveProfileToGlobal(profileName, gamePath string, multiplier int, performanceMode bool, dllPath, gpu, flowScale, pacing string, allowFp16 bool) error {
@@ -166,7 +247,12 @@ func DisableProfileInConfig(profileName, gamePath string) error {
 	if err != nil {
 		return err
 	}
+	return DisableProfileAtPath(profileName, gamePath, configPath)
+}
 
+// DisableProfileAtPath stops the profile from applying to gamePath. Version 1
+// configs cannot list executables, so the game's table is removed instead.
+func DisableProfileAtPath(profileName, gamePath, configPath string) error {
 	data, err := os.ReadFile(configPath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -175,32 +261,37 @@ func DisableProfileInConfig(profileName, gamePath string) error {
 		return fmt.Errorf("failed to read LSFG config: %w", err)
 	}
 
-	var config ConfigFile
-	if err := toml.Unmarshal(data, &config); err != nil {
-		return fmt.Errorf("failed to parse LSFG config: %w", err)
+	document, err := ParseDocument(data)
+	if err != nil {
+		return err
 	}
 
 	executableName := filepath.Base(gamePath)
-	found := false
-
-	for index, profile := range config.Profiles {
-		if matchesProfile(strings.ToLower(executableName), profile.ActiveIn) || strings.EqualFold(profile.Name, profileName) {
-			config.Profiles[index].ActiveIn = ""
-			found = true
-			break
-		}
-	}
-
+	index, found := findProfile(document, executableName, profileName)
 	if !found {
 		return nil
 	}
 
-	data, err = toml.Marshal(config)
-	if err != nil {
-		return fmt.Errorf("failed to marshal LSFG config: %w", err)
+	tableName, matchKey := profileLayout(document.Version())
+	table, _ := document.tableAt(tableName, true, index)
+	if document.Version() == 1 {
+		document.removeTable(table)
+		return document.Save(configPath)
 	}
 
-	return os.WriteFile(configPath, data, 0644)
+	current, _ := document.getValue(table, matchKey)
+	if _, isList := current.([]interface{}); isList {
+		err = document.removeFromArray(table, matchKey, func(item interface{}) bool {
+			name, ok := item.(string)
+			return ok && strings.EqualFold(name, executableName)
+		})
+	} else {
+		err = document.setValue(table, matchKey, removeActiveIn(current, executableName))
+	}
+	if err != nil {
+		return err
+	}
+	return document.Save(configPath)
 }
 
 func RemoveProfileFromConfig(gamePath string) error {
@@ -214,31 +305,21 @@ func RemoveProfileFromConfig(gamePath string) error {
 		return fmt.Errorf("failed to read LSFG config: %w", err)
 	}
 
-	var config ConfigFile
-	if err := toml.Unmarshal(data, &config); err != nil {
-		return fmt.Errorf("failed to parse LSFG config: %w", err)
+	document, err := ParseDocument(data)
+	if err != nil {
+		return err
 	}
 
 	executableName := filepath.Base(gamePath)
-	found := false
-	for index, profile := range config.Profiles {
-		if matchesProfile(strings.ToLower(executableName), profile.ActiveIn) {
-			config.Profiles = append(config.Profiles[:index], config.Profiles[index+1:]...)
-			found = true
-			break
-		}
-	}
-
+	index, found := findProfile(document, executableName, "")
 	if !found {
 		return fmt.Errorf("no profile found for %s", executableName)
 	}
 
-	data, err = toml.Marshal(config)
-	if err != nil {
-		return fmt.Errorf("failed to marshal LSFG config: %w", err)
-	}
-
-	return os.WriteFile(configPath, data, 0644)
+	tableName, _ := profileLayout(document.Version())
+	table, _ := document.tableAt(tableName, true, index)
+	document.removeTable(table)
+	return document.Save(configPath)
 }
 
 func EditConfigForGame(gamePath string) error {
@@ -259,6 +340,16 @@ func openFileWithEditor(filePath string) error {
 	return command.Start()
 }
 
+func ParseMultiplier(multiplier string) int {
+	value := 2
+	if multiplier != "" {
+		if _, err := fmt.Sscanf(multiplier, "%d", &value); err != nil {
+			value = 2
+		}
+	}
+	return value
+}
+
 func parseFlowScale(flowScale string) float32 {
 	var value float32 = 1.0
 	if flowScale != "" {
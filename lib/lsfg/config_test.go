@@ -0,0 +1,165 @@
+package lsfg
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func TestConfigEditsGolden(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		edit  func(configPath string) error
+	}{
+		{
+			name:  "v2_update_existing",
+			input: "v2_comments.toml",
+			edit: func(configPath string) error {
+				return SaveProfileToPath("First", "/games/first.exe", configPath, 3, true, "/home/user/Lossless.dll", "", "0.5", "none", true)
+			},
+		},
+		{
+			name:  "v2_add_profile",
+			input: "v2_comments.toml",
+			edit: func(configPath string) error {
+				return SaveProfileToPath("Third", "/games/third.exe", configPath, 2, false, "/home/user/Lossless.dll", "", "1.0", "none", true)
+			},
+		},
+		{
+			name:  "v2_disable_inline",
+			input: "v2_comments.toml",
+			edit: func(configPath string) error {
+				return DisableProfileAtPath("Second", "/games/second.exe", configPath)
+			},
+		},
+		{
+			name:  "v2_disable_inline_last",
+			input: "v2_comments.toml",
+			edit: func(configPath string) error {
+				return DisableProfileAtPath("Second", "/games/other.exe", configPath)
+			},
+		},
+		{
+			name:  "v2_append_inline",
+			input: "v2_comments.toml",
+			edit: func(configPath string) error {
+				return SaveProfileToPath("Second", "/games/third.exe", configPath, 3, false, "/home/user/Lossless.dll", "", "1.0", "none", true)
+			},
+		},
+		{
+			name:  "v2_multiline_append",
+			input: "v2_multiline.toml",
+			edit: func(configPath string) error {
+				return SaveProfileToPath("Shared", "/games/z.exe", configPath, 2, false, "", "", "1.0", "none", false)
+			},
+		},
+		{
+			name:  "v2_multiline_disable_last",
+			input: "v2_multiline.toml",
+			edit: func(configPath string) error {
+				return DisableProfileAtPath("Shared", "/games/b.exe", configPath)
+			},
+		},
+		{
+			name:  "v2_multiline_disable_commented",
+			input: "v2_multiline.toml",
+			edit: func(configPath string) error {
+				return DisableProfileAtPath("Shared", "/games/a.exe", configPath)
+			},
+		},
+		{
+			name:  "v2_multiline_no_trailing_comma",
+			input: "v2_multiline_no_comma.toml",
+			edit: func(configPath string) error {
+				return DisableProfileAtPath("Shared", "/games/b.exe", configPath)
+			},
+		},
+		{
+			name:  "v1_update_existing",
+			input: "v1.toml",
+			edit: func(configPath string) error {
+				return SaveProfileToPath("", "/games/second.exe", configPath, 4, true, "/games/Lossless.dll", "", "0.7", "none", true)
+			},
+		},
+		{
+			name:  "v1_add_game",
+			input: "v1.toml",
+			edit: func(configPath string) error {
+				return SaveProfileToPath("", "/games/third.exe", configPath, 2, false, "/games/Lossless.dll", "", "1.0", "none", true)
+			},
+		},
+		{
+			name:  "v1_disable",
+			input: "v1.toml",
+			edit: func(configPath string) error {
+				return DisableProfileAtPath("", "/games/first.exe", configPath)
+			},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			input, err := os.ReadFile(filepath.Join("testdata", testCase.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			configPath := filepath.Join(t.TempDir(), "conf.toml")
+			if err := os.WriteFile(configPath, input, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := testCase.edit(configPath); err != nil {
+				t.Fatalf("edit failed: %v", err)
+			}
+			got, err := os.ReadFile(configPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := ReadConfigFile(configPath); err != nil {
+				t.Fatalf("edited config does not parse: %v\n%s", err, got)
+			}
+
+			goldenPath := filepath.Join("testdata", testCase.name+".golden")
+			if *update {
+				if err := os.WriteFile(goldenPath, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("edited config differs from %s\n--- got ---\n%s\n--- want ---\n%s", goldenPath, got, want)
+			}
+		})
+	}
+}
+
+func TestConfigEditsUnchangedWhenSavingSameValues(t *testing.T) {
+	input, err := os.ReadFile(filepath.Join("testdata", "v2_multiline.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(t.TempDir(), "conf.toml")
+	if err := os.WriteFile(configPath, input, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SaveProfileToPath("Shared", "/games/a.exe", configPath, 2, false, "", "", "1.0", "none", false); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, input) {
+		t.Errorf("saving identical values rewrote the config:\n%s", got)
+	}
+}
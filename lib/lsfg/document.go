@@ -0,0 +1,669 @@
+package lsfg
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// Document is an lsfg-vk config kept as its original text. Edits replace
+// only the values they touch, so comments, formatting and keys LightLauncher
+// does not know about survive a save.
+type Document struct {
+	data []byte
+}
+
+// tableSpan locates one table in the document. The root table has no header
+// and starts at offset zero.
+type tableSpan struct {
+	name      string
+	array     bool
+	start     int
+	bodyStart int
+	end       int
+}
+
+// entrySpan locates one key/value pair inside a table.
+type entrySpan struct {
+	key        string
+	start      int
+	valueStart int
+	valueEnd   int
+	lineEnd    int
+}
+
+func NewDocument() *Document {
+	return &Document{data: []byte("version = 2\n\n[global]\nversion = 2\n")}
+}
+
+func ParseDocument(data []byte) (*Document, error) {
+	var check map[string]interface{}
+	if err := toml.Unmarshal(data, &check); err != nil {
+		return nil, fmt.Errorf("failed to parse LSFG config: %w", err)
+	}
+	return &Document{data: append([]byte(nil), data...)}, nil
+}
+
+// LoadDocument reads the config at path, or starts a new one if it is missing.
+func LoadDocument(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return NewDocument(), nil
+		}
+		return nil, fmt.Errorf("failed to read LSFG config: %w", err)
+	}
+	return ParseDocument(data)
+}
+
+func (doc *Document) Bytes() []byte {
+	return doc.data
+}
+
+func (doc *Document) Save(path string) error {
+	if _, err := ParseDocument(doc.data); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, doc.data, 0644)
+}
+
+// Version returns the config format version, treating files without one as
+// the current format.
+func (doc *Document) Version() int {
+	var header struct {
+		Version int `toml:"version"`
+	}
+	if err := toml.Unmarshal(doc.data, &header); err != nil || header.Version == 0 {
+		return 2
+	}
+	return header.Version
+}
+
+// Decode converts the document into a ConfigFile. Version 1 [[game]] tables
+// are mapped onto profiles so callers only deal with one shape.
+func (doc *Document) Decode() (*ConfigFile, error) {
+	var config ConfigFile
+	if err := toml.Unmarshal(doc.data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse LSFG config: %w", err)
+	}
+
+	if doc.Version() == 1 {
+		config.Global.AllowFP16 = !config.Global.NoFP16
+		for _, game := range config.Games {
+			config.Profiles = append(config.Profiles, ConfigProfile{
+				Name:            strings.TrimSuffix(game.Exe, filepath.Ext(game.Exe)),
+				ActiveIn:        game.Exe,
+				Multiplier:      game.Multiplier,
+				PerformanceMode: game.PerformanceMode,
+				FlowScale:       game.FlowScale,
+			})
+		}
+	}
+	return &config, nil
+}
+
+func (doc *Document) tables() []tableSpan {
+	tables := []tableSpan{{start: 0, bodyStart: 0}}
+	position := 0
+	for position < len(doc.data) {
+		lineStart := position
+		content := skipSpaces(doc.data, position)
+
+		if content < len(doc.data) && doc.data[content] == '[' {
+			lineEnd := nextLine(doc.data, content)
+			header := strings.TrimSpace(stripComment(string(doc.data[content:lineEnd])))
+			table := tableSpan{start: lineStart, bodyStart: lineEnd}
+			if strings.HasPrefix(header, "[[") {
+				table.array = true
+				table.name = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(header, "[["), "]]"))
+			} else {
+				table.name = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(header, "["), "]"))
+			}
+			tables[len(tables)-1].end = lineStart
+			tables = append(tables, table)
+			position = lineEnd
+			continue
+		}
+
+		if content < len(doc.data) && doc.data[content] != '#' && doc.data[content] != '\n' && doc.data[content] != '\r' {
+			if equals := bytes.IndexByte(doc.data[content:], '='); equals >= 0 {
+				position = nextLine(doc.data, scanValueEnd(doc.data, skipSpaces(doc.data, content+equals+1)))
+				continue
+			}
+		}
+		position = nextLine(doc.data, content)
+	}
+	tables[len(tables)-1].end = len(doc.data)
+	return tables
+}
+
+func (doc *Document) entries(table tableSpan) []entrySpan {
+	var entries []entrySpan
+	position := table.bodyStart
+	for position < table.end {
+		content := skipSpaces(doc.data, position)
+		if content >= table.end || doc.data[content] == '#' || doc.data[content] == '\n' || doc.data[content] == '\r' {
+			position = nextLine(doc.data, content)
+			continue
+		}
+
+		equals := bytes.IndexByte(doc.data[content:], '=')
+		if equals < 0 {
+			position = nextLine(doc.data, content)
+			continue
+		}
+		valueStart := skipSpaces(doc.data, content+equals+1)
+		valueEnd := scanValueEnd(doc.data, valueStart)
+		lineEnd := nextLine(doc.data, valueEnd)
+		key := strings.Trim(strings.TrimSpace(string(doc.data[content:content+equals])), `"'`)
+		entries = append(entries, entrySpan{key: key, start: position, valueStart: valueStart, valueEnd: valueEnd, lineEnd: lineEnd})
+		position = lineEnd
+	}
+	return entries
+}
+
+// tableAt returns the nth table with the given name and kind. Spans move
+// with every edit, so callers look tables up again after changing the text.
+func (doc *Document) tableAt(name string, array bool, occurrence int) (tableSpan, bool) {
+	for _, table := range doc.tables() {
+		if table.name != name || table.array != array {
+			continue
+		}
+		if occurrence == 0 {
+			return table, true
+		}
+		occurrence--
+	}
+	return tableSpan{}, false
+}
+
+func (doc *Document) countTables(name string, array bool) int {
+	count := 0
+	for _, table := range doc.tables() {
+		if table.name == name && table.array == array {
+			count++
+		}
+	}
+	return count
+}
+
+// setValues sets several keys of the nth matching table in order.
+func (doc *Document) setValues(name string, array bool, occurrence int, keys []string, values map[string]interface{}) error {
+	for _, key := range keys {
+		table, found := doc.tableAt(name, array, occurrence)
+		if !found {
+			return fmt.Errorf("LSFG config has no %s table", name)
+		}
+		if err := doc.setValue(table, key, values[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getValue decodes the value stored under key in table.
+func (doc *Document) getValue(table tableSpan, key string) (interface{}, bool) {
+	for _, entry := range doc.entries(table) {
+		if entry.key != key {
+			continue
+		}
+		var holder map[string]interface{}
+		if err := toml.Unmarshal([]byte("v = "+string(doc.data[entry.valueStart:entry.valueEnd])), &holder); err != nil {
+			return nil, false
+		}
+		return holder["v"], true
+	}
+	return nil, false
+}
+
+// setValue replaces the value of key in table, or adds the key after the
+// table's last entry. Everything around the value is left untouched.
+func (doc *Document) setValue(table tableSpan, key string, value interface{}) error {
+	literal, err := formatValue(value)
+	if err != nil {
+		return err
+	}
+
+	entries := doc.entries(table)
+	for _, entry := range entries {
+		if entry.key != key {
+			continue
+		}
+		// Leave equal values alone so their original spelling survives.
+		if current, found := doc.getValue(table, key); found {
+			if currentLiteral, err := formatValue(current); err == nil && currentLiteral == literal {
+				return nil
+			}
+		}
+		doc.replace(entry.valueStart, entry.valueEnd, literal)
+		return nil
+	}
+
+	insertAt := table.bodyStart
+	if len(entries) > 0 {
+		insertAt = entries[len(entries)-1].lineEnd
+	}
+	line := key + " = " + literal + "\n"
+	if insertAt > 0 && doc.data[insertAt-1] != '\n' {
+		line = "\n" + line
+	}
+	doc.replace(insertAt, insertAt, line)
+	return nil
+}
+
+// appendToArray adds value to the array stored under key, writing it next to
+// the existing elements so the array keeps its layout and comments.
+func (doc *Document) appendToArray(table tableSpan, key string, value interface{}) error {
+	literal, err := formatValue(value)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range doc.entries(table) {
+		if entry.key != key {
+			continue
+		}
+		closing := entry.valueEnd - 1
+		if closing <= entry.valueStart || doc.data[entry.valueStart] != '[' || doc.data[closing] != ']' {
+			return fmt.Errorf("LSFG config key %s is not an array", key)
+		}
+
+		lastElement := lastArrayElementEnd(doc.data, entry.valueStart+1, closing)
+		separator := ""
+		if lastElement > entry.valueStart+1 && doc.data[lastElement-1] != ',' {
+			separator = ","
+		}
+
+		lineStart := bytes.LastIndexByte(doc.data[:closing], '\n') + 1
+		if lineStart <= entry.valueStart || strings.TrimSpace(string(doc.data[lineStart:closing])) != "" {
+			if separator != "" {
+				separator += " "
+			}
+			doc.replace(lastElement, lastElement, separator+literal)
+			return nil
+		}
+
+		elementLine := bytes.LastIndexByte(doc.data[:lastElement], '\n') + 1
+		indentation := string(doc.data[elementLine:skipSpaces(doc.data, elementLine)])
+		doc.replace(lineStart, lineStart, indentation+literal+",\n")
+		if separator != "" {
+			doc.replace(lastElement, lastElement, separator)
+		}
+		return nil
+	}
+	return doc.setValue(table, key, []interface{}{value})
+}
+
+// arrayElement locates one element of an array value. next is the offset
+// just past the comma following the element, or end when there is none.
+type arrayElement struct {
+	start int
+	end   int
+	next  int
+}
+
+// arrayElements lists the elements of the array between the brackets at
+// start and end, skipping whitespace and comments.
+func arrayElements(data []byte, start, end int) []arrayElement {
+	var elements []arrayElement
+	position := start
+	for position < end {
+		switch {
+		case data[position] == '#':
+			position = nextLine(data, position)
+			continue
+		case data[position] == ' ' || data[position] == '\t' || data[position] == '\r' || data[position] == '\n' || data[position] == ',':
+			position++
+			continue
+		}
+
+		element := arrayElement{start: position, end: elementEnd(data, position, end), next: end}
+		position = element.end
+		for position < end && (data[position] == ' ' || data[position] == '\t' || data[position] == '\r' || data[position] == '\n') {
+			position++
+		}
+		if position < end && data[position] == ',' {
+			position++
+			element.next = position
+		}
+		elements = append(elements, element)
+	}
+	return elements
+}
+
+// elementEnd returns the offset just past the array element at position.
+func elementEnd(data []byte, position, end int) int {
+	depth := 0
+	for position < end {
+		switch {
+		case data[position] == '"' || data[position] == '\'':
+			terminator := string(data[position])
+			if bytes.HasPrefix(data[position:], []byte(strings.Repeat(terminator, 3))) {
+				terminator = strings.Repeat(terminator, 3)
+			}
+			position = skipString(data, position+len(terminator), terminator, terminator[0] == '"')
+		case data[position] == '[' || data[position] == '{':
+			depth++
+			position++
+		case data[position] == ']' || data[position] == '}':
+			depth--
+			position++
+		case depth == 0 && (data[position] == ',' || data[position] == '#' || data[position] == ' ' || data[position] == '\t' || data[position] == '\r' || data[position] == '\n'):
+			return position
+		default:
+			position++
+		}
+	}
+	return position
+}
+
+// removeFromArray deletes the elements of the array under key for which
+// matches returns true. An element on a line of its own is removed together
+// with that line and its trailing comment; otherwise only the element and
+// its comma go, so the rest of the array keeps its layout.
+func (doc *Document) removeFromArray(table tableSpan, key string, matches func(interface{}) bool) error {
+	for _, entry := range doc.entries(table) {
+		if entry.key != key {
+			continue
+		}
+		closing := entry.valueEnd - 1
+		if closing <= entry.valueStart || doc.data[entry.valueStart] != '[' || doc.data[closing] != ']' {
+			return fmt.Errorf("LSFG config key %s is not an array", key)
+		}
+
+		elements := arrayElements(doc.data, entry.valueStart+1, closing)
+		for index := len(elements) - 1; index >= 0; index-- {
+			element := elements[index]
+			var holder map[string]interface{}
+			if err := toml.Unmarshal([]byte("v = "+string(doc.data[element.start:element.end])), &holder); err != nil || !matches(holder["v"]) {
+				continue
+			}
+
+			lineStart := bytes.LastIndexByte(doc.data[:element.start], '\n') + 1
+			lineEnd := nextLine(doc.data, element.start)
+			after := element.next
+			if after == closing {
+				after = element.end
+			}
+			ownLine := lineStart > entry.valueStart && lineEnd <= closing &&
+				strings.TrimSpace(string(doc.data[lineStart:element.start])) == "" &&
+				after <= lineEnd && strings.TrimSpace(stripComment(string(doc.data[after:lineEnd]))) == ""
+			switch {
+			case ownLine:
+				doc.replace(lineStart, lineEnd, "")
+				if element.next == closing && index > 0 {
+					previous := elements[index-1]
+					doc.replace(previous.next-1, previous.next, "")
+				}
+			case element.next == closing && index > 0:
+				// Last element without a trailing comma: drop the comma before
+				// it, keeping any comment that sits between the two.
+				previous := elements[index-1]
+				if bytes.IndexByte(doc.data[previous.end:element.start], '#') < 0 {
+					doc.replace(previous.end, element.end, "")
+					break
+				}
+				doc.replace(element.start, element.end, "")
+				doc.replace(previous.next-1, previous.next, "")
+			default:
+				removeEnd := element.next
+				if element.next != closing {
+					removeEnd = skipSpaces(doc.data, element.next)
+				}
+				doc.replace(element.start, removeEnd, "")
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+// lastArrayElementEnd returns the offset just past the last element or comma
+// between start and end, ignoring whitespace and comments.
+func lastArrayElementEnd(data []byte, start, end int) int {
+	last := start
+	position := start
+	for position < end {
+		switch {
+		case data[position] == '#':
+			position = nextLine(data, position)
+		case data[position] == '"' || data[position] == '\'':
+			terminator := string(data[position])
+			if bytes.HasPrefix(data[position:], []byte(strings.Repeat(terminator, 3))) {
+				terminator = strings.Repeat(terminator, 3)
+			}
+			position = skipString(data, position+len(terminator), terminator, terminator[0] == '"')
+			last = position
+		case data[position] == ' ' || data[position] == '\t' || data[position] == '\r' || data[position] == '\n':
+			position++
+		default:
+			position++
+			last = position
+		}
+	}
+	return last
+}
+
+// removeTable deletes a table with its entries and the comment lines directly
+// above its header. Comments after the last entry usually describe the next
+// table, so they are kept.
+func (doc *Document) removeTable(table tableSpan) {
+	start := table.start
+	for start > 0 {
+		previous := bytes.LastIndexByte(doc.data[:start-1], '\n') + 1
+		if !strings.HasPrefix(strings.TrimSpace(string(doc.data[previous:start])), "#") {
+			break
+		}
+		start = previous
+	}
+
+	end := table.bodyStart
+	if entries := doc.entries(table); len(entries) > 0 {
+		end = entries[len(entries)-1].lineEnd
+	}
+	for end < table.end {
+		content := skipSpaces(doc.data, end)
+		if content < len(doc.data) && (doc.data[content] == '\n' || doc.data[content] == '\r') {
+			end = nextLine(doc.data, content)
+			continue
+		}
+		break
+	}
+	doc.replace(start, end, "")
+}
+
+// appendTable adds a [name] or [[name]] table at the end of the document.
+func (doc *Document) appendTable(name string, array bool, keys []string, values map[string]interface{}) error {
+	var table strings.Builder
+	if len(doc.data) > 0 && !bytes.HasSuffix(doc.data, []byte("\n")) {
+		table.WriteString("\n")
+	}
+	if len(doc.data) > 0 && !bytes.HasSuffix(doc.data, []byte("\n\n")) {
+		table.WriteString("\n")
+	}
+	if array {
+		table.WriteString("[[" + name + "]]\n")
+	} else {
+		table.WriteString("[" + name + "]\n")
+	}
+	for _, key := range keys {
+		literal, err := formatValue(values[key])
+		if err != nil {
+			return err
+		}
+		table.WriteString(key + " = " + literal + "\n")
+	}
+	doc.data = append(doc.data, table.String()...)
+	return nil
+}
+
+func (doc *Document) replace(start, end int, text string) {
+	updated := make([]byte, 0, len(doc.data)-(end-start)+len(text))
+	updated = append(updated, doc.data[:start]...)
+	updated = append(updated, text...)
+	updated = append(updated, doc.data[end:]...)
+	doc.data = updated
+}
+
+// formatValue writes value as a TOML literal. Strings use double quotes,
+// which is what lsfg-vk's own config template uses.
+func formatValue(value interface{}) (string, error) {
+	switch typed := value.(type) {
+	case string:
+		return quoteString(typed), nil
+	case bool:
+		return strconv.FormatBool(typed), nil
+	case int:
+		return strconv.Itoa(typed), nil
+	case int64:
+		return strconv.FormatInt(typed, 10), nil
+	case float32:
+		return formatFloat(float64(typed), 32), nil
+	case float64:
+		return formatFloat(typed, 64), nil
+	case []interface{}:
+		elements := make([]string, 0, len(typed))
+		for _, element := range typed {
+			literal, err := formatValue(element)
+			if err != nil {
+				return "", err
+			}
+			elements = append(elements, literal)
+		}
+		return "[" + strings.Join(elements, ", ") + "]", nil
+	case []string:
+		elements := make([]interface{}, 0, len(typed))
+		for _, element := range typed {
+			elements = append(elements, element)
+		}
+		return formatValue(elements)
+	}
+	return "", fmt.Errorf("failed to format LSFG value of type %T", value)
+}
+
+func quoteString(value string) string {
+	var quoted strings.Builder
+	quoted.WriteByte('"')
+	for _, character := range value {
+		switch character {
+		case '"':
+			quoted.WriteString(`\"`)
+		case '\\':
+			quoted.WriteString(`\\`)
+		case '\n':
+			quoted.WriteString(`\n`)
+		case '\t':
+			quoted.WriteString(`\t`)
+		case '\r':
+			quoted.WriteString(`\r`)
+		default:
+			if character < 0x20 || character == 0x7f {
+				fmt.Fprintf(&quoted, `\u%04X`, character)
+			} else {
+				quoted.WriteRune(character)
+			}
+		}
+	}
+	quoted.WriteByte('"')
+	return quoted.String()
+}
+
+func formatFloat(value float64, bits int) string {
+	literal := strconv.FormatFloat(value, 'f', -1, bits)
+	if !strings.ContainsAny(literal, ".eE") {
+		literal += ".0"
+	}
+	return literal
+}
+
+func skipSpaces(data []byte, position int) int {
+	for position < len(data) && (data[position] == ' ' || data[position] == '\t') {
+		position++
+	}
+	return position
+}
+
+// nextLine returns the offset just past the newline ending the line at position.
+func nextLine(data []byte, position int) int {
+	if newline := bytes.IndexByte(data[position:], '\n'); newline >= 0 {
+		return position + newline + 1
+	}
+	return len(data)
+}
+
+func stripComment(line string) string {
+	if index := strings.IndexByte(line, '#'); index >= 0 {
+		return line[:index]
+	}
+	return line
+}
+
+// scanValueEnd finds where the value starting at position ends, skipping
+// strings, multi-line arrays and inline tables. Trailing spaces and comments
+// are not part of the value.
+func scanValueEnd(data []byte, position int) int {
+	depth := 0
+	end := position
+	for position < len(data) {
+		switch {
+		case bytes.HasPrefix(data[position:], []byte(`"""`)):
+			position = skipString(data, position+3, `"""`, true)
+			end = position
+		case bytes.HasPrefix(data[position:], []byte(`'''`)):
+			position = skipString(data, position+3, `'''`, false)
+			end = position
+		case data[position] == '"':
+			position = skipString(data, position+1, `"`, true)
+			end = position
+		case data[position] == '\'':
+			position = skipString(data, position+1, `'`, false)
+			end = position
+		case data[position] == '#':
+			if depth == 0 {
+				return end
+			}
+			position = nextLine(data, position)
+		case data[position] == '\n':
+			if depth == 0 {
+				return end
+			}
+			position++
+		case data[position] == '[' || data[position] == '{':
+			depth++
+			position++
+			end = position
+		case data[position] == ']' || data[position] == '}':
+			depth--
+			position++
+			end = position
+		case data[position] == ' ' || data[position] == '\t' || data[position] == '\r':
+			position++
+		default:
+			position++
+			end = position
+		}
+	}
+	return end
+}
+
+func skipString(data []byte, position int, terminator string, escapes bool) int {
+	for position < len(data) {
+		if escapes && data[position] == '\\' {
+			position += 2
+			continue
+		}
+		if bytes.HasPrefix(data[position:], []byte(terminator)) {
+			return position + len(terminator)
+		}
+		position++
+	}
+	return len(data)
+}
@@ -2,6 +2,12 @@ package lsfg
 
 const (
 	Repo = "PancakeTAS/lsfg-vk"
+
+	// lsfg-vk 1.x reads the LSFG_ variables, 2.x the LSFGVK_ ones.
+	ConfigEnvironment    = "LSFG_CONFIG"
+	ProcessEnvironment   = "LSFG_PROCESS"
+	ConfigEnvironmentV2  = "LSFGVK_CONFIG"
+	ProfileEnvironmentV2 = "LSFGVK_PROFILE"
 )
 
 type Status struct {
@@ -12,6 +18,7 @@ type Status struct {
 type GlobalConfig struct {
 	Version   int    `toml:"version"`
 	AllowFP16 bool   `toml:"allow_fp16"`
+	NoFP16    bool   `toml:"no_fp16"` // version 1 only
 	DLL       string `toml:"dll"`
 }
 
@@ -25,10 +32,19 @@ type ConfigProfile struct {
 	Pacing          string      `toml:"pacing"`
 }
 
+// LegacyGameProfile is a version 1 [[game]] table, matched by exe name only.
+type LegacyGameProfile struct {
+	Exe             string  `toml:"exe"`
+	Multiplier      int     `toml:"multiplier"`
+	FlowScale       float32 `toml:"flow_scale"`
+	PerformanceMode bool    `toml:"performance_mode"`
+}
+
 type ConfigFile struct {
-	Version  int             `toml:"version"`
-	Global   GlobalConfig    `toml:"global"`
-	Profiles []ConfigProfile `toml:"profile"`
+	Version  int                 `toml:"version"`
+	Global   GlobalConfig        `toml:"global"`
+	Profiles []ConfigProfile     `toml:"profile"`
+	Games    []LegacyGameProfile `toml:"game"`
 }
 
 type InternalProfile struct {
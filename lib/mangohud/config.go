@@ -0,0 +1,161 @@
+package mangohud
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Config holds the MangoHud.conf settings LightLauncher manages. Lines it
+// does not manage are kept in ExtraLines.
+type Config struct {
+	Preset        string
+	Position      string
+	FpsLimit      string
+	ShowFps       bool
+	ShowFrametime bool
+	ShowCpu       bool
+	ShowGpu       bool
+	ShowCpuTemp   bool
+	ShowGpuTemp   bool
+	ShowRam       bool
+	ShowVram      bool
+	ShowBattery   bool
+	LogFolder     string
+	ExtraLines    []string
+	// Toggled names the parameters of the switches that were set explicitly.
+	// Render writes only these, so the others keep the preset's value.
+	Toggled []string
+}
+
+// toggles maps MangoHud parameters to the Config switches.
+var toggles = []struct {
+	key   string
+	value func(*Config) *bool
+}{
+	{"fps", func(c *Config) *bool { return &c.ShowFps }},
+	{"frametime", func(c *Config) *bool { return &c.ShowFrametime }},
+	{"cpu_stats", func(c *Config) *bool { return &c.ShowCpu }},
+	{"gpu_stats", func(c *Config) *bool { return &c.ShowGpu }},
+	{"cpu_temp", func(c *Config) *bool { return &c.ShowCpuTemp }},
+	{"gpu_temp", func(c *Config) *bool { return &c.ShowGpuTemp }},
+	{"ram", func(c *Config) *bool { return &c.ShowRam }},
+	{"vram", func(c *Config) *bool { return &c.ShowVram }},
+	{"battery", func(c *Config) *bool { return &c.ShowBattery }},
+}
+
+func GetGlobalConfigPath() (string, error) {
+	if configHome := os.Getenv("XDG_CONFIG_HOME"); configHome != "" {
+		return filepath.Join(configHome, "MangoHud", "MangoHud.conf"), nil
+	}
+	homeDirectory, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDirectory, ".config", "MangoHud", "MangoHud.conf"), nil
+}
+
+// Render produces MangoHud.conf content for cfg. Lines LightLauncher does not
+// manage are kept in ExtraLines and written back unchanged.
+func Render(cfg Config) string {
+	var content strings.Builder
+	content.WriteString("# Generated by LightLauncher\n")
+
+	if cfg.Preset != "" {
+		content.WriteString("preset=" + cfg.Preset + "\n")
+	}
+	if cfg.Position != "" {
+		content.WriteString("position=" + cfg.Position + "\n")
+	}
+	if cfg.FpsLimit != "" {
+		content.WriteString("fps_limit=" + cfg.FpsLimit + "\n")
+	}
+	// Switches left alone are not written, since any value would override
+	// the preset.
+	for _, toggle := range toggles {
+		if !isToggled(cfg, toggle.key) {
+			continue
+		}
+		if *toggle.value(&cfg) {
+			content.WriteString(toggle.key + "=1\n")
+		} else {
+			content.WriteString(toggle.key + "=0\n")
+		}
+	}
+	if cfg.LogFolder != "" {
+		content.WriteString("output_folder=" + cfg.LogFolder + "\n")
+	}
+	for _, line := range cfg.ExtraLines {
+		content.WriteString(line + "\n")
+	}
+	return content.String()
+}
+
+func Write(path string, cfg Config) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(Render(cfg)), 0644)
+}
+
+// Load reads an existing MangoHud config file so it can seed per-game settings.
+func Load(path string) (*Config, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read MangoHud config: %w", err)
+	}
+	defer file.Close()
+
+	var cfg Config
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, hasValue := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "preset":
+			cfg.Preset = value
+		case "position":
+			cfg.Position = value
+		case "fps_limit":
+			cfg.FpsLimit = value
+		case "output_folder":
+			cfg.LogFolder = value
+		default:
+			if !applyToggle(&cfg, key, value, hasValue) {
+				cfg.ExtraLines = append(cfg.ExtraLines, line)
+			}
+		}
+	}
+	return &cfg, scanner.Err()
+}
+
+func applyToggle(cfg *Config, key, value string, hasValue bool) bool {
+	for _, toggle := range toggles {
+		if toggle.key == key {
+			*toggle.value(cfg) = !hasValue || value != "0"
+			if !isToggled(*cfg, key) {
+				cfg.Toggled = append(cfg.Toggled, key)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func isToggled(cfg Config, key string) bool {
+	for _, toggled := range cfg.Toggled {
+		if toggled == key {
+			return true
+		}
+	}
+	return false
+}
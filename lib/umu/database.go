@@ -0,0 +1,193 @@
+package umu
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DatabaseURL is the CSV export of the umu-database that Refresh downloads.
+const DatabaseURL = "https://raw.githubusercontent.com/Open-Wine-Components/umu-database/main/umu-database.csv"
+
+// csvColumns maps the umu-database CSV headers to Entry fields. Optional
+// columns carry a suffix such as "(Optional)", so headers match by prefix.
+var csvColumns = []struct {
+	header string
+	value  func(*Entry) *string
+}{
+	{"TITLE", func(e *Entry) *string { return &e.Title }},
+	{"STORE", func(e *Entry) *string { return &e.Store }},
+	{"CODENAME", func(e *Entry) *string { return &e.Codename }},
+	{"UMU_ID", func(e *Entry) *string { return &e.UmuID }},
+	{"COMMON ACRONYM", func(e *Entry) *string { return &e.Acronym }},
+	{"NOTE", func(e *Entry) *string { return &e.Notes }},
+}
+
+// Entry is one row of the umu-database. Codename is the game's ID in its
+// store, e.g. the Steam app ID or the Epic codename.
+type Entry struct {
+	Title    string
+	Store    string
+	Codename string
+	UmuID    string
+	Acronym  string
+	Notes    string
+	ExeNames []string
+}
+
+// apiEntry is an entry of the umu API's JSON export.
+type apiEntry struct {
+	Title     string `json:"title"`
+	Store     string `json:"store"`
+	Codename  string `json:"codename"`
+	UmuID     string `json:"umu_id"`
+	Acronym   string `json:"acronym"`
+	Notes     string `json:"notes"`
+	ExeString string `json:"exe_string"`
+}
+
+// Load reads a umu-database file, either the CSV from the repository or the
+// JSON the umu API returns. A missing file is an empty database.
+func Load(path string) ([]Entry, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read umu database: %w", err)
+	}
+	return Parse(data)
+}
+
+func Parse(data []byte) ([]Entry, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		return parseJSON(trimmed)
+	}
+	return parseCSV(trimmed)
+}
+
+func parseJSON(data []byte) ([]Entry, error) {
+	var rows []apiEntry
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return nil, fmt.Errorf("invalid umu database: %w", err)
+	}
+	entries := make([]Entry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, Entry{
+			Title:    row.Title,
+			Store:    row.Store,
+			Codename: row.Codename,
+			UmuID:    row.UmuID,
+			Acronym:  row.Acronym,
+			Notes:    row.Notes,
+			ExeNames: splitExeNames(row.ExeString),
+		})
+	}
+	return entries, nil
+}
+
+func parseCSV(data []byte) ([]Entry, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid umu database: %w", err)
+	}
+
+	fields := make([]func(*Entry) *string, len(header))
+	exeColumn := -1
+	for index, name := range header {
+		name = strings.ToUpper(strings.TrimSpace(name))
+		if strings.HasPrefix(name, "EXE_STRING") {
+			exeColumn = index
+			continue
+		}
+		for _, column := range csvColumns {
+			if strings.HasPrefix(name, column.header) {
+				fields[index] = column.value
+				break
+			}
+		}
+	}
+
+	var entries []Entry
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid umu database: %w", err)
+		}
+
+		var entry Entry
+		for index, value := range record {
+			if index == exeColumn {
+				entry.ExeNames = splitExeNames(value)
+			} else if index < len(fields) && fields[index] != nil {
+				*fields[index](&entry) = strings.TrimSpace(value)
+			}
+		}
+		if entry.UmuID != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func splitExeNames(value string) []string {
+	var names []string
+	for _, name := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' }) {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Refresh downloads the current umu-database to path.
+func Refresh(path string) ([]Entry, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	request, err := http.NewRequest("GET", DatabaseURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("User-Agent", "LightLauncher-App")
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download umu database: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download umu database: %s", response.Status)
+	}
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download umu database: %w", err)
+	}
+	entries, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
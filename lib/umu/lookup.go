@@ -0,0 +1,96 @@
+package umu
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultGameID is what umu-run falls back to without a GAMEID, which
+// applies no protonfixes.
+const DefaultGameID = "umu-default"
+
+// Stores are the STORE values umu-protonfixes has fixes for.
+var Stores = []string{"steam", "egs", "gog", "amazon", "battlenet", "ea", "humble", "itchio", "ubisoft", "zoomplatform"}
+
+// Match ranks the entries that fit a game: a store ID match first, then an
+// exact executable name, then a title or acronym matching the executable.
+// With a store set, the store ID only matches entries from that store, since
+// IDs from different stores can collide.
+func Match(entries []Entry, exePath, store, storeID string) []Entry {
+	exeName := strings.ToLower(filepath.Base(exePath))
+	exeStem := normalize(strings.TrimSuffix(exeName, filepath.Ext(exeName)))
+	store = Store(store)
+	storeID = strings.ToLower(strings.TrimSpace(storeID))
+
+	type candidate struct {
+		entry Entry
+		score int
+	}
+	var candidates []candidate
+	for _, entry := range entries {
+		score := 0
+		switch {
+		case storeID != "" && strings.ToLower(entry.Codename) == storeID && (store == "" || Store(entry.Store) == store):
+			score = 3
+		case exePath != "" && containsExe(entry.ExeNames, exeName):
+			score = 2
+		case exeStem != "" && (normalize(entry.Title) == exeStem || normalize(entry.Acronym) == exeStem):
+			score = 1
+		}
+		if score > 0 {
+			candidates = append(candidates, candidate{entry, score})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return candidates[i].entry.Title < candidates[j].entry.Title
+	})
+
+	matches := make([]Entry, 0, len(candidates))
+	for _, candidate := range candidates {
+		matches = append(matches, candidate.entry)
+	}
+	return matches
+}
+
+// GameID returns the GAMEID for id. A bare Steam app ID gets the umu- prefix
+// umu-run expects.
+func GameID(id string) string {
+	id = strings.TrimSpace(id)
+	if id != "" && strings.IndexFunc(id, func(r rune) bool { return !unicode.IsDigit(r) }) == -1 {
+		return "umu-" + id
+	}
+	return id
+}
+
+// Store returns the STORE value for store, or "" for games outside a store.
+func Store(store string) string {
+	store = strings.ToLower(strings.TrimSpace(store))
+	if store == "none" {
+		return ""
+	}
+	return store
+}
+
+func containsExe(names []string, exeName string) bool {
+	for _, name := range names {
+		if strings.ToLower(filepath.Base(name)) == exeName {
+			return true
+		}
+	}
+	return false
+}
+
+func normalize(value string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, value)
+}
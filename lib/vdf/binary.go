@@ -0,0 +1,213 @@
+package vdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Binary VDF type markers as used by Steam's shortcuts.vdf.
+const (
+	typeMap     byte = 0x00
+	typeString  byte = 0x01
+	typeInt32   byte = 0x02
+	typeFloat32 byte = 0x03
+	typeUint64  byte = 0x07
+	typeMapEnd  byte = 0x08
+)
+
+// Map is an ordered binary VDF object. Values are string, uint32, float32,
+// uint64 or *Map; order is kept so unchanged files round-trip byte for byte.
+// Keys are matched case-insensitively, as Steam does.
+type Map struct {
+	Entries []Entry
+}
+
+type Entry struct {
+	Key   string
+	Value interface{}
+}
+
+func NewMap() *Map {
+	return &Map{}
+}
+
+func (m *Map) Get(key string) (interface{}, bool) {
+	for _, entry := range m.Entries {
+		if strings.EqualFold(entry.Key, key) {
+			return entry.Value, true
+		}
+	}
+	return nil, false
+}
+
+func (m *Map) GetString(key string) string {
+	value, _ := m.Get(key)
+	str, _ := value.(string)
+	return str
+}
+
+func (m *Map) GetMap(key string) *Map {
+	value, _ := m.Get(key)
+	child, _ := value.(*Map)
+	return child
+}
+
+// Set replaces the value of key in place or appends it when missing.
+func (m *Map) Set(key string, value interface{}) {
+	for index := range m.Entries {
+		if strings.EqualFold(m.Entries[index].Key, key) {
+			m.Entries[index].Value = value
+			return
+		}
+	}
+	m.Entries = append(m.Entries, Entry{Key: key, Value: value})
+}
+
+func (m *Map) Delete(key string) {
+	for index := range m.Entries {
+		if strings.EqualFold(m.Entries[index].Key, key) {
+			m.Entries = append(m.Entries[:index], m.Entries[index+1:]...)
+			return
+		}
+	}
+}
+
+// ParseBinary decodes a binary VDF document into its root map.
+func ParseBinary(data []byte) (*Map, error) {
+	reader := &binaryReader{data: data}
+	root, err := reader.readMap()
+	if err != nil {
+		return nil, err
+	}
+	if reader.position < len(data) && data[reader.position] == typeMapEnd {
+		reader.position++
+	}
+	return root, nil
+}
+
+// MarshalBinary encodes m as a binary VDF document.
+func (m *Map) MarshalBinary() ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := writeMap(&buffer, m); err != nil {
+		return nil, err
+	}
+	buffer.WriteByte(typeMapEnd)
+	return buffer.Bytes(), nil
+}
+
+type binaryReader struct {
+	data     []byte
+	position int
+}
+
+func (reader *binaryReader) readMap() (*Map, error) {
+	result := NewMap()
+	for {
+		if reader.position >= len(reader.data) {
+			return result, nil
+		}
+
+		valueType := reader.data[reader.position]
+		reader.position++
+		if valueType == typeMapEnd {
+			return result, nil
+		}
+
+		key, err := reader.readString()
+		if err != nil {
+			return nil, err
+		}
+
+		var value interface{}
+		switch valueType {
+		case typeMap:
+			value, err = reader.readMap()
+		case typeString:
+			value, err = reader.readString()
+		case typeInt32:
+			var raw []byte
+			raw, err = reader.read(4)
+			if err == nil {
+				value = binary.LittleEndian.Uint32(raw)
+			}
+		case typeFloat32:
+			var raw []byte
+			raw, err = reader.read(4)
+			if err == nil {
+				value = math.Float32frombits(binary.LittleEndian.Uint32(raw))
+			}
+		case typeUint64:
+			var raw []byte
+			raw, err = reader.read(8)
+			if err == nil {
+				value = binary.LittleEndian.Uint64(raw)
+			}
+		default:
+			return nil, fmt.Errorf("unknown vdf type 0x%02x at offset %d", valueType, reader.position-1)
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Entries = append(result.Entries, Entry{Key: key, Value: value})
+	}
+}
+
+func (reader *binaryReader) readString() (string, error) {
+	end := bytes.IndexByte(reader.data[reader.position:], 0)
+	if end < 0 {
+		return "", fmt.Errorf("unterminated vdf string at offset %d", reader.position)
+	}
+	value := string(reader.data[reader.position : reader.position+end])
+	reader.position += end + 1
+	return value, nil
+}
+
+func (reader *binaryReader) read(size int) ([]byte, error) {
+	if reader.position+size > len(reader.data) {
+		return nil, fmt.Errorf("unexpected end of vdf data at offset %d", reader.position)
+	}
+	value := reader.data[reader.position : reader.position+size]
+	reader.position += size
+	return value, nil
+}
+
+func writeMap(buffer *bytes.Buffer, m *Map) error {
+	for _, entry := range m.Entries {
+		switch value := entry.Value.(type) {
+		case *Map:
+			writeKey(buffer, typeMap, entry.Key)
+			if err := writeMap(buffer, value); err != nil {
+				return err
+			}
+			buffer.WriteByte(typeMapEnd)
+		case string:
+			writeKey(buffer, typeString, entry.Key)
+			buffer.WriteString(value)
+			buffer.WriteByte(0)
+		case uint32:
+			writeKey(buffer, typeInt32, entry.Key)
+			_ = binary.Write(buffer, binary.LittleEndian, value)
+		case int32:
+			writeKey(buffer, typeInt32, entry.Key)
+			_ = binary.Write(buffer, binary.LittleEndian, value)
+		case float32:
+			writeKey(buffer, typeFloat32, entry.Key)
+			_ = binary.Write(buffer, binary.LittleEndian, math.Float32bits(value))
+		case uint64:
+			writeKey(buffer, typeUint64, entry.Key)
+			_ = binary.Write(buffer, binary.LittleEndian, value)
+		default:
+			return fmt.Errorf("unsupported vdf value %T for key %q", entry.Value, entry.Key)
+		}
+	}
+	return nil
+}
+
+func writeKey(buffer *bytes.Buffer, valueType byte, key string) {
+	buffer.WriteByte(valueType)
+	buffer.WriteString(key)
+	buffer.WriteByte(0)
+}
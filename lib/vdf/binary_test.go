@@ -0,0 +1,57 @@
+package vdf
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	input, err := os.ReadFile(filepath.Join("testdata", "shortcuts.vdf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root, err := ParseBinary(input)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	got, err := root.MarshalBinary()
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	if !bytes.Equal(got, input) {
+		t.Errorf("round trip changed shortcuts.vdf\n--- got ---\n%q\n--- want ---\n%q", got, input)
+	}
+}
+
+func TestShortcutsSaveUnchanged(t *testing.T) {
+	input, err := os.ReadFile(filepath.Join("testdata", "shortcuts.vdf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	shortcutsPath := filepath.Join(t.TempDir(), "shortcuts.vdf")
+	if err := os.WriteFile(shortcutsPath, input, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	shortcuts, err := LoadShortcuts(shortcutsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count := len(shortcuts.Entries()); count != 3 {
+		t.Fatalf("found %d shortcuts, want 3", count)
+	}
+	if err := shortcuts.Save(shortcutsPath); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(shortcutsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, input) {
+		t.Errorf("saving unchanged shortcuts rewrote the file\n--- got ---\n%q\n--- want ---\n%q", got, input)
+	}
+}
@@ -0,0 +1,89 @@
+package vdf
+
+import (
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// Shortcuts is the list of non-Steam games stored in
+// userdata/<id>/config/shortcuts.vdf.
+type Shortcuts struct {
+	root *Map
+	list *Map
+}
+
+// LoadShortcuts reads a shortcuts.vdf file. A missing file yields an empty list.
+func LoadShortcuts(path string) (*Shortcuts, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			root := NewMap()
+			list := NewMap()
+			root.Set("shortcuts", list)
+			return &Shortcuts{root: root, list: list}, nil
+		}
+		return nil, err
+	}
+
+	root, err := ParseBinary(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	list := root.GetMap("shortcuts")
+	if list == nil {
+		list = NewMap()
+		root.Set("shortcuts", list)
+	}
+	return &Shortcuts{root: root, list: list}, nil
+}
+
+func (shortcuts *Shortcuts) Save(path string) error {
+	data, err := shortcuts.root.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func (shortcuts *Shortcuts) Entries() []*Map {
+	var entries []*Map
+	for _, entry := range shortcuts.list.Entries {
+		if shortcut, ok := entry.Value.(*Map); ok {
+			entries = append(entries, shortcut)
+		}
+	}
+	return entries
+}
+
+// Find returns the first shortcut accepted by match, or nil.
+func (shortcuts *Shortcuts) Find(match func(*Map) bool) *Map {
+	for _, shortcut := range shortcuts.Entries() {
+		if match(shortcut) {
+			return shortcut
+		}
+	}
+	return nil
+}
+
+// Add appends a shortcut under the next free index key.
+func (shortcuts *Shortcuts) Add(shortcut *Map) {
+	next := 0
+	for _, entry := range shortcuts.list.Entries {
+		if index, err := strconv.Atoi(entry.Key); err == nil && index >= next {
+			next = index + 1
+		}
+	}
+	shortcuts.list.Set(strconv.Itoa(next), shortcut)
+}
+
+// ShortcutAppID computes a non-Steam shortcut app ID the way Steam does from
+// the shortcut's Exe and name. Any stable key can stand in for the Exe.
+func ShortcutAppID(key, appName string) uint32 {
+	return crc32.ChecksumIEEE([]byte(key+appName)) | 0x80000000
+}
@@ -8,10 +8,35 @@ export const DEFAULT_LAUNCH_OPTIONS: core.LaunchOptions = {
 		UseGamePath: false,
 		PrefixPath: "",
 		ProtonPath: "",
+		Backend: "",
+		WinePath: "",
 		CustomArgs: "",
+		UmuGameID: "",
+		UmuStore: "",
+		Environment: [],
+		PreLaunchHooks: [],
+		PostExitHooks: [],
 		Extras: {
 			EnableMangoHud: false,
 			EnableGamemode: false,
+			MangoHud: {
+				PerGame: false,
+				Preset: "",
+				Position: "",
+				FpsLimit: "",
+				ShowFps: true,
+				ShowFrametime: true,
+				ShowCpu: true,
+				ShowGpu: true,
+				ShowCpuTemp: false,
+				ShowGpuTemp: false,
+				ShowRam: false,
+				ShowVram: false,
+				ShowBattery: false,
+				LogFolder: "",
+				ExtraLines: [],
+				ChangedToggles: [],
+			},
 			Lsfg: {
 				Enabled: false,
 				Multiplier: "2",
@@ -27,11 +52,51 @@ export const DEFAULT_LAUNCH_OPTIONS: core.LaunchOptions = {
 				Width: "1920",
 				Height: "1080",
 				RefreshRate: "60",
+				OutputWidth: "",
+				OutputHeight: "",
+				Fullscreen: false,
+				Borderless: false,
+				Filter: "",
+				Scaler: "",
+				Sharpness: "",
+				FrameLimit: "",
+				HDR: false,
+				ForceGrabCursor: false,
+				AdaptiveSync: false,
+				MangoApp: false,
 			},
 			Memory: {
 				Enabled: false,
 				Value: "4G",
 			},
+			Scope: {
+				Enabled: false,
+				MemoryMax: "",
+				MemoryHigh: "",
+				CPUQuota: "",
+				CPUWeight: "",
+				IOWeight: "",
+				AllowedCPUs: "",
+			},
+			GpuDevice: "",
+			Proton: {
+				DisableEsync: false,
+				DisableFsync: false,
+				EnableNtsync: false,
+				UseWineD3D: false,
+				EnableWayland: false,
+				EnableNvapi: false,
+				HideNvidiaGpu: false,
+				LargeAddressAware: false,
+			},
+			Scheduling: {
+				Enabled: false,
+				CpuSet: "",
+				Nice: "",
+				IoniceClass: "",
+				IoniceLevel: "",
+				IdlePriority: false,
+			},
 		},
 	} as core.LaunchOptions),
 };
@@ -53,6 +118,25 @@ export const GAMESCOPE_DEFAULTS = {
 	refreshRate: "60",
 };
 
+export const GAMESCOPE_FILTERS = ["linear", "nearest", "fsr", "nis", "pixel"];
+
+export const GAMESCOPE_SCALERS = ["auto", "integer", "fit", "fill", "stretch"];
+
+export const IONICE_CLASSES = ["realtime", "best-effort", "idle"];
+
+export const MANGOHUD_POSITIONS = [
+	"top-left",
+	"top-center",
+	"top-right",
+	"middle-left",
+	"middle-right",
+	"bottom-left",
+	"bottom-center",
+	"bottom-right",
+];
+
+export const MANGOHUD_PRESETS = ["0", "1", "2", "3", "4"];
+
 export const MEMORY_DEFAULTS = {
 	value: "4G",
 	min: 512,
@@ -39,6 +39,10 @@ export function mergeOptions(existing: core.LaunchOptions, loaded: Partial<core.
 		...loaded,
 	};
 
+	merged.Environment = loaded.Environment || existing.Environment || [];
+	merged.PreLaunchHooks = loaded.PreLaunchHooks || existing.PreLaunchHooks || [];
+	merged.PostExitHooks = loaded.PostExitHooks || existing.PostExitHooks || [];
+
 	if (loaded.Extras) {
 		merged.Extras = {
 			...existing.Extras,
@@ -50,6 +54,13 @@ export function mergeOptions(existing: core.LaunchOptions, loaded: Partial<core.
 				...loaded.Extras.Lsfg,
 			};
 		}
+		if (loaded.Extras.MangoHud) {
+			merged.Extras.MangoHud = {
+				...existing.Extras.MangoHud,
+				...loaded.Extras.MangoHud,
+				ExtraLines: loaded.Extras.MangoHud.ExtraLines || [],
+			};
+		}
 		if (loaded.Extras.Gamescope) {
 			merged.Extras.Gamescope = {
 				...existing.Extras.Gamescope,
@@ -62,6 +73,24 @@ export function mergeOptions(existing: core.LaunchOptions, loaded: Partial<core.
 				...loaded.Extras.Memory,
 			};
 		}
+		if (loaded.Extras.Proton) {
+			merged.Extras.Proton = {
+				...existing.Extras.Proton,
+				...loaded.Extras.Proton,
+			};
+		}
+		if (loaded.Extras.Scheduling) {
+			merged.Extras.Scheduling = {
+				...existing.Extras.Scheduling,
+				...loaded.Extras.Scheduling,
+			};
+		}
+		if (loaded.Extras.Scope) {
+			merged.Extras.Scope = {
+				...existing.Extras.Scope,
+				...loaded.Extras.Scope,
+			};
+		}
 	}
 
 	return merged;
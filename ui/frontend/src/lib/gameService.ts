@@ -12,6 +12,14 @@ export interface ScannedExecutable {
 	icon: string | null;
 }
 
+/**
+ * Derives a game name from an executable path, dropping the extension of
+ * Windows executables, AppImages and scripts
+ */
+export function gameNameFromPath(path: string): string {
+	return path.split("/").pop()?.replace(/\.(exe|appimage|sh|x86_64)$/i, "") || "Game";
+}
+
 /**
  * Scans a folder for game executables
  */
@@ -25,7 +33,7 @@ export async function scanFolderForExecutables(
 		if (executables && executables.length > 0) {
 			return executables.map((path) => ({
 				path,
-				name: path.split("/").pop()?.replace(".exe", "") || "Game",
+				name: gameNameFromPath(path),
 				icon: null,
 			}));
 		}
@@ -43,7 +51,7 @@ export async function registerGame(
 	executablePath: string,
 	prefixPath: string
 ): Promise<void> {
-	const gameName = executablePath.split("/").pop()?.replace(".exe", "") || "Game";
+	const gameName = gameNameFromPath(executablePath);
 	
 	let losslessDllPath = "";
 	try {
@@ -4,12 +4,16 @@ import {
 	ListPrefixes,
 	RunGame,
 	KillSession,
+	ForceKillSession,
+	ToggleSessionLogs,
 	RemoveGame,
 	GetPrefixBaseDir,
 	SaveGameConfig,
+	GetExecutableKind,
 } from "@bindings/light-launcher/internal/app/app";
 import { notifications } from "@stores/notificationStore";
 import { createLaunchOptions } from "./formService";
+import { gameNameFromPath } from "./gameService";
 
 export interface HomeData {
 	games: any[];
@@ -57,14 +61,40 @@ export async function quickLaunchGame(game: any): Promise<void> {
  */
 export async function terminateSession(processId: number, gameName: string): Promise<void> {
 	try {
-		await KillSession(processId);
-		notifications.add(`Terminated session: ${gameName}`, "success");
+		const result = await KillSession(processId);
+		const stage = result?.stage ? ` (${result.stage})` : "";
+		notifications.add(`Terminated session: ${gameName}${stage}`, "success");
 	} catch (error) {
 		notifications.add(`Failed to kill session: ${error}`, "error");
 		throw error;
 	}
 }
 
+/**
+ * Kills a session's whole process group immediately
+ */
+export async function forceKillSession(processId: number, gameName: string): Promise<void> {
+	try {
+		await ForceKillSession(processId);
+		notifications.add(`Force killed session: ${gameName}`, "success");
+	} catch (error) {
+		notifications.add(`Failed to force kill session: ${error}`, "error");
+		throw error;
+	}
+}
+
+/**
+ * Opens or closes the log terminal of a session
+ */
+export async function toggleSessionLogs(processId: number): Promise<boolean> {
+	try {
+		return await ToggleSessionLogs(processId);
+	} catch (error) {
+		notifications.add(`Failed to toggle logs: ${error}`, "error");
+		throw error;
+	}
+}
+
 /**
  * Removes multiple games in bulk
  */
@@ -92,8 +122,9 @@ export async function processDroppedFiles(filePaths: string[]): Promise<number>
 		const defaultPrefixPath = `${basePrefixDirectory}/Default`;
 
 		for (const filePath of filePaths) {
-			if (filePath.toLowerCase().endsWith(".exe")) {
-				const gameName = filePath.split("/").pop()?.replace(".exe", "") || "Game";
+			const kind = filePath.toLowerCase().endsWith(".exe") ? "windows" : await GetExecutableKind(filePath);
+			if (kind) {
+				const gameName = gameNameFromPath(filePath);
 				
 				const gameConfig = createLaunchOptions();
 				gameConfig.Name = gameName;
@@ -92,6 +92,9 @@ export function applyConfigToOptions(
 
 	options.Name = config.Name || options.Name;
 	options.CustomArgs = config.CustomArgs || "";
+	options.Environment = structuredClone(config.Environment || []);
+	options.PreLaunchHooks = structuredClone(config.PreLaunchHooks || []);
+	options.PostExitHooks = structuredClone(config.PostExitHooks || []);
 	
 	// Copy Extras
 	if (config.Extras) {
@@ -8,6 +8,8 @@ import {
 	ListPrefixes,
 	GetSystemToolsStatus,
 	RunGame,
+	RunGameDebug,
+	GetExecutableKind,
 } from "@bindings/light-launcher/internal/app/app";
 import * as core from "@bindings/light-launcher/internal/types/models";
 import { notifications } from "@stores/notificationStore";
@@ -143,6 +145,16 @@ export async function initializeRunPage(
 	};
 }
 
+/**
+ * Whether the launch goes through umu-run. Without an explicit backend,
+ * Linux executables, AppImages and scripts run natively.
+ */
+export async function usesUmu(launchOptions: core.LaunchOptions): Promise<boolean> {
+	if (launchOptions.Backend) return launchOptions.Backend === "umu";
+	const kind = await GetExecutableKind(launchOptions.LauncherPath || launchOptions.GamePath);
+	return kind !== "native" && kind !== "appimage" && kind !== "script";
+}
+
 /**
  * Validates dependencies and environment before launching the game.
  * Returns true if the validation modal should be shown to the user.
@@ -152,7 +164,8 @@ export async function validateAndLaunch(
 	systemStatus: core.SystemToolsStatus,
 	selectedProtonName: string,
 	protonVersions: core.ProtonTool[],
-	showLogsWindow: boolean
+	showLogsWindow: boolean,
+	debugPreset = ""
 ): Promise<boolean> {
 	if (!launchOptions.LauncherPath) {
 		notifications.add("Please select a launcher executable.", "error");
@@ -170,29 +183,37 @@ export async function validateAndLaunch(
 	if (launchOptions.Extras.EnableMangoHud && !systemStatus.hasMangoHud) missingTools.push("MangoHud");
 	if (launchOptions.Extras.EnableGamemode && !systemStatus.hasGameMode) missingTools.push("GameMode");
 	if (launchOptions.Extras.Lsfg.Enabled && !systemStatus.hasVulkanInfo) missingTools.push("Vulkan-Tools");
+	if (!systemStatus.hasUmu && (await usesUmu(launchOptions))) missingTools.push("umu-launcher");
+	if (launchOptions.Backend === "wine" && !launchOptions.WinePath && !systemStatus.hasWine) missingTools.push("Wine");
 
 	if (missingTools.length > 0) {
 		return true; // Show modal
 	}
 
-	await executeLaunch(launchOptions, selectedProtonName, protonVersions, showLogsWindow);
+	await executeLaunch(launchOptions, selectedProtonName, protonVersions, showLogsWindow, debugPreset);
 	return false;
 }
 
 /**
  * Directly executes the game launch without further validation.
+ * A debug preset launches with Proton/Wine debug logging enabled.
  */
 export async function executeLaunch(
 	launchOptions: core.LaunchOptions,
 	selectedProtonName: string,
 	protonVersions: core.ProtonTool[],
-	showLogsWindow: boolean
+	showLogsWindow: boolean,
+	debugPreset = ""
 ): Promise<void> {
 	const matchedProton = protonVersions.find((tool) => tool.DisplayName === selectedProtonName);
 	launchOptions.ProtonPath = matchedProton ? matchedProton.Path : (selectedProtonName.includes("/") ? selectedProtonName : "");
 
 	try {
-		await RunGame(launchOptions, showLogsWindow);
+		if (debugPreset) {
+			await RunGameDebug(launchOptions, showLogsWindow, debugPreset);
+		} else {
+			await RunGame(launchOptions, showLogsWindow);
+		}
 		Window.Close();
 	} catch (error) {
 		console.error("[EXECUTE] Launch failed:", error);
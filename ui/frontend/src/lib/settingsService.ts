@@ -37,6 +37,18 @@ export async function toggleTransparentMode(currentSettings: any): Promise<void>
 	}
 }
 
+/**
+ * Saves application settings that apply without a restart
+ */
+export async function saveAppSettings(settings: any): Promise<void> {
+	try {
+		await SaveAppSettings(settings);
+	} catch (err) {
+		notifications.add("Failed to save setting", "error");
+		throw err;
+	}
+}
+
 /**
  * Opens a file picker for background images
  */